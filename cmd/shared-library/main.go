@@ -2,8 +2,9 @@ package main
 import "C"
 import (
 	"context"
+	"github.com/configwizard/gaspump-api/pkg/balance"
+	client2 "github.com/configwizard/gaspump-api/pkg/client"
 	"github.com/configwizard/gaspump-api/pkg/wallet"
-	"github.com/nspcc-dev/neofs-sdk-go/client"
 	"sync"
 )
 
@@ -15,21 +16,15 @@ func Test() string {
 }
 //export RetrieveNeoFSBalance
 func RetrieveNeoFSBalance(walletPath, password string) (int64, error) {
+	mtx.Lock()
+	defer mtx.Unlock()
 	ctx := context.Background()
 	// First obtain client credentials: private key of request owner
 	key, err := wallet.GetCredentialsFromPath(walletPath, "", password)
 	if err != nil {
 		return 0, err
 	}
-	defer mtx.Unlock()
-	cli, err := client.New(
-		// provide private key associated with request owner
-		client.WithDefaultPrivateKey(key),
-		// find endpoints in https://testcdn.fs.neo.org/doc/integrations/endpoints/
-		client.WithURIAddress("grpcs://st01.testnet.fs.neo.org:8082", nil),
-		// check client errors in go compatible way
-		client.WithNeoFSErrorParsing(),
-	)
+	cli, err := client2.NewClient(key, client2.TESTNET)
 	if err != nil {
 		return 0, err
 	}
@@ -38,9 +33,10 @@ func RetrieveNeoFSBalance(walletPath, password string) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
-	get := client.PrmBalanceGet{}
-	get.SetAccount(*owner)
-	result, err := cli.BalanceGet(ctx, get)
-	return result.Amount().Value(), err
+	b, err := balance.GetBalance(ctx, cli, owner)
+	if err != nil {
+		return 0, err
+	}
+	return b.Value, nil
 }
 func main() {}
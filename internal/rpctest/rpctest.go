@@ -0,0 +1,186 @@
+// Package rpctest is a fake Neo N3 JSON-RPC node for tests. It answers the handshake of neo-go's client and
+// RPCClient, serves the contracts added to it and passes any other method to the handlers set with Handle
+package rpctest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/request"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/assert"
+)
+
+// Error is a JSON-RPC error answer, return it from a Handler to choose the code
+type Error struct {
+	Code    int64
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ErrUnknown is how nodes answer for a transaction or contract they don't have
+func ErrUnknown(what string) *Error {
+	return &Error{Code: -100, Message: "Unknown " + what}
+}
+
+// Handler answers a method with its result, or an error
+type Handler func(params []interface{}) (interface{}, error)
+
+// Node is a JSON-RPC server on network. Neo, GAS and Policy are deployed as natives with the hashes 05, 06 and 07
+type Node struct {
+	*httptest.Server
+	t       *testing.T
+	network netmode.Magic
+	calls   int32
+
+	mu        sync.Mutex
+	contracts []state.Contract
+	natives   []state.NativeContract
+	handlers  map[string]Handler
+}
+
+// NewContract is a contract with an empty script, supporting standards
+func NewContract(t *testing.T, name string, id int32, hash util.Uint160, standards ...string) state.Contract {
+	f, err := nef.NewFile([]byte{0x40})
+	assert.Nil(t, err, "error not nil")
+	m := manifest.DefaultManifest(name)
+	m.SupportedStandards = standards
+	return state.Contract{ContractBase: state.ContractBase{ID: id, Hash: hash, NEF: *f, Manifest: *m}}
+}
+
+// NewNode starts a node, it is closed when the test ends
+func NewNode(t *testing.T, network netmode.Magic) *Node {
+	n := &Node{t: t, network: network, handlers: make(map[string]Handler)}
+	n.AddNative(NewContract(t, nativenames.Neo, -5, util.Uint160{5}, manifest.NEP17StandardName))
+	n.AddNative(NewContract(t, nativenames.Gas, -6, util.Uint160{6}, manifest.NEP17StandardName))
+	n.AddNative(NewContract(t, nativenames.Policy, -7, util.Uint160{7}))
+	n.Server = httptest.NewServer(http.HandlerFunc(n.serve))
+	t.Cleanup(n.Close)
+	return n
+}
+
+// AddContract deploys c, getcontractstate finds it by name, hash or id
+func (n *Node) AddContract(c state.Contract) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.contracts = append(n.contracts, c)
+}
+
+// AddNative deploys c and lists it in getnativecontracts
+func (n *Node) AddNative(c state.Contract) {
+	n.AddContract(c)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.natives = append(n.natives, state.NativeContract{ContractBase: c.ContractBase})
+}
+
+// Handle answers method with h, replacing the node's own answer if it has one
+func (n *Node) Handle(method string, h Handler) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.handlers[method] = h
+}
+
+// Calls is how many requests the node has received
+func (n *Node) Calls() int32 {
+	return atomic.LoadInt32(&n.calls)
+}
+
+func (n *Node) contract(param interface{}) (state.Contract, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, c := range n.contracts {
+		switch p := param.(type) {
+		case float64:
+			if int32(p) == c.ID {
+				return c, true
+			}
+		case string:
+			if p == c.Manifest.Name || p == c.Hash.StringLE() || p == "0x"+c.Hash.StringLE() {
+				return c, true
+			}
+		}
+	}
+	return state.Contract{}, false
+}
+
+func (n *Node) serve(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&n.calls, 1)
+	var req request.Raw
+	assert.Nil(n.t, json.NewDecoder(r.Body).Decode(&req), "error not nil")
+	n.mu.Lock()
+	h, ok := n.handlers[req.Method]
+	n.mu.Unlock()
+
+	var res interface{}
+	var err error
+	switch {
+	case ok:
+		res, err = h(req.RawParams)
+	case req.Method == "getversion":
+		res = result.Version{Protocol: result.Protocol{Network: n.network, MillisecondsPerBlock: 15000}}
+	case req.Method == "getnativecontracts":
+		n.mu.Lock()
+		res = n.natives
+		n.mu.Unlock()
+	case req.Method == "getcontractstate":
+		var found bool
+		if res, found = n.contract(req.RawParams[0]); !found {
+			err = ErrUnknown("contract")
+		}
+	default:
+		err = &Error{Code: -32601, Message: "Method not found"}
+	}
+	if err != nil {
+		rpcErr := &Error{Code: -32603, Message: err.Error()}
+		errors.As(err, &rpcErr)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID,
+			"error": map[string]interface{}{"code": rpcErr.Code, "message": rpcErr.Message}})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": res})
+}
+
+// Call answers a contract call made by a script, with the arguments as passed
+type Call func(contract util.Uint160, method string, args []stackitem.Item) stackitem.Item
+
+// InvokeScript is an invokescript Handler that runs the script, answering each contract call it makes with call
+func InvokeScript(t *testing.T, call Call) Handler {
+	return func(params []interface{}) (interface{}, error) {
+		var script []byte
+		if err := json.Unmarshal([]byte(`"`+params[0].(string)+`"`), &script); err != nil {
+			return nil, err
+		}
+		v := vm.New()
+		v.SyscallHandler = func(v *vm.VM, _ uint32) error {
+			hash, err := util.Uint160DecodeBytesBE(v.Estack().Pop().Bytes())
+			assert.Nil(t, err, "error not nil")
+			method := v.Estack().Pop().String()
+			v.Estack().Pop() // call flags
+			args := v.Estack().Pop().Array()
+			v.Estack().PushItem(call(hash, method, args))
+			return nil
+		}
+		v.LoadScript(script)
+		if err := v.Run(); err != nil {
+			return nil, err
+		}
+		return result.Invoke{State: "HALT", Stack: []stackitem.Item{v.Estack().Pop().Item()}}, nil
+	}
+}
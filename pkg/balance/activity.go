@@ -0,0 +1,77 @@
+package balance
+
+import (
+	"context"
+
+	wallet2 "github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+type ActivityKind string
+
+const (
+	ACTIVITY_SENT       ActivityKind = "sent"
+	ACTIVITY_RECEIVED   ActivityKind = "received"
+	ACTIVITY_DEPOSIT    ActivityKind = "deposit"
+	ACTIVITY_WITHDRAWAL ActivityKind = "withdrawal"
+)
+
+// Activity is a transfer labelled with what it means for the account. GAS sent to the NeoFS contract is a deposit
+// and GAS paid out by it is a withdrawal
+type Activity struct {
+	Kind ActivityKind `json:"kind"`
+	wallet2.Nep17HistoryEntry
+}
+
+// ActivityPage is a page of account activity, newest first, with the amounts of the page summed per kind and token symbol
+type ActivityPage struct {
+	Address string                                     `json:"address"`
+	Page    int                                        `json:"page"`
+	Entries []Activity                                 `json:"entries"`
+	Totals  map[ActivityKind]map[string]wallet2.Amount `json:"totals"`
+	More    bool                                       `json:"more"`
+}
+
+// GetActivity returns a page of the transfers of walletAddress with its NeoFS deposits and withdrawals picked out.
+// contract is the NeoFS contract, see NeoFSContract
+func GetActivity(ctx context.Context, rpc *wallet2.RPCClient, contract util.Uint160, walletAddress string, q wallet2.HistoryQuery) (*ActivityPage, error) {
+	history, err := wallet2.GetNep17History(ctx, rpc, walletAddress, q)
+	if err != nil {
+		return nil, err
+	}
+	gas, err := rpc.NativeHash(nativenames.Gas)
+	if err != nil {
+		return nil, err
+	}
+	page := &ActivityPage{
+		Address: history.Address,
+		Page:    history.Page,
+		Entries: make([]Activity, 0, len(history.Entries)),
+		Totals:  map[ActivityKind]map[string]wallet2.Amount{},
+		More:    history.More,
+	}
+	for _, e := range history.Entries {
+		a := Activity{Kind: KindOf(e, gas, contract), Nep17HistoryEntry: e}
+		page.Entries = append(page.Entries, a)
+
+		totals, ok := page.Totals[a.Kind]
+		if !ok {
+			totals = map[string]wallet2.Amount{}
+			page.Totals[a.Kind] = totals
+		}
+		totals[e.Symbol] = totals[e.Symbol].Add(e.Amount)
+	}
+	return page, nil
+}
+
+// KindOf labels a transfer given the GAS token and the NeoFS contract
+func KindOf(e wallet2.Nep17HistoryEntry, gas, contract util.Uint160) ActivityKind {
+	if e.Token.Equals(gas) && e.Counterparty == wallet2.Uint160ToString(contract) {
+		if e.Direction == wallet2.TRANSFER_SENT {
+			return ACTIVITY_DEPOSIT
+		}
+		return ACTIVITY_WITHDRAWAL
+	}
+	return ActivityKind(e.Direction)
+}
@@ -0,0 +1,239 @@
+package balance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	wallet2 "github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/nspcc-dev/neofs-sdk-go/accounting"
+	"github.com/nspcc-dev/neofs-sdk-go/client"
+	"github.com/nspcc-dev/neofs-sdk-go/owner"
+)
+
+const (
+	// NEOFS_CONTRACT_MAINNET and NEOFS_CONTRACT_TESTNET are the NeoFS contracts on the Neo main chain. GAS sent to them
+	// is credited to the sender's NeoFS balance
+	NEOFS_CONTRACT_MAINNET = "NNxVrKjLsRkWsmGgmuNXLcMswtxTGaNQLk"
+	NEOFS_CONTRACT_TESTNET = "NadZ8YfvkddivcFFkztZgfwxZyKf1acpRF"
+	// GAS_DECIMALS is the precision of GAS on the main chain
+	GAS_DECIMALS = 8
+	// DEFAULT_POLL_INTERVAL is how often transactions and balances are checked while waiting
+	DEFAULT_POLL_INTERVAL = 5 * time.Second
+)
+
+// Balance is an amount of GAS held in NeoFS. Value is in units of 10^-Precision GAS
+type Balance struct {
+	Value     int64  `json:"value"`
+	Precision uint32 `json:"precision"`
+}
+
+// NewBalance converts the SDK's decimal
+func NewBalance(d *accounting.Decimal) *Balance {
+	if d == nil {
+		return &Balance{}
+	}
+	return &Balance{Value: d.Value(), Precision: d.Precision()}
+}
+
+// Convert returns the value at another precision, truncating if precision is lower
+func (b *Balance) Convert(precision uint32) int64 {
+	v, _ := b.Amount().Truncate(int64(precision)).Int64()
+	return v
+}
+
+// GAS returns the balance in fixed8 GAS units, as used for transfers
+func (b *Balance) GAS() int64 {
+	return b.Convert(GAS_DECIMALS)
+}
+
+// Amount is the balance as a token amount at its own precision
+func (b *Balance) Amount() wallet2.Amount {
+	return wallet2.AmountFromInt64(b.Value, int64(b.Precision))
+}
+
+// String formats the balance in whole GAS, e.g. 12.5
+func (b *Balance) String() string {
+	return b.Amount().String()
+}
+
+// NeoFSContract returns the main chain NeoFS contract for a network
+func NeoFSContract(network wallet2.RPC_NETWORK) (util.Uint160, error) {
+	switch network {
+	case wallet2.RPC_MAINNET:
+		return wallet2.StringToUint160(NEOFS_CONTRACT_MAINNET)
+	case wallet2.RPC_TESTNET:
+		return wallet2.StringToUint160(NEOFS_CONTRACT_TESTNET)
+	}
+	return util.Uint160{}, fmt.Errorf("no NeoFS contract known for %s", network)
+}
+
+// GetBalance returns the NeoFS balance of an account
+func GetBalance(ctx context.Context, cli *client.Client, ownerID *owner.ID) (*Balance, error) {
+	prm := client.PrmBalanceGet{}
+	prm.SetAccount(*ownerID)
+	res, err := cli.BalanceGet(ctx, prm)
+	if err != nil {
+		return nil, fmt.Errorf("can't get NeoFS balance: %w", err)
+	}
+	return NewBalance(res.Amount()), nil
+}
+
+// Result describes a completed deposit or withdrawal
+type Result struct {
+	TxHash  util.Uint256 `json:"txHash"`
+	GasUsed int64        `json:"gasUsed"`
+	Before  *Balance     `json:"before"`
+	After   *Balance     `json:"after"`
+}
+
+// Deposit transfers amount of GAS from acc to the NeoFS contract, then waits for the NeoFS balance of the
+// receiver to increase. receiver may be nil to credit acc itself. ctx bounds the whole operation, give it a deadline
+func Deposit(ctx context.Context, cli *client.Client, rpc *wallet2.RPCClient, acc *wallet.Account, contract util.Uint160, amount wallet2.Amount, receiver *util.Uint160) (*Result, error) {
+	fixed8, err := gasUnits(amount)
+	if err != nil {
+		return nil, err
+	}
+	credited := acc.Contract.ScriptHash()
+	var data interface{}
+	if receiver != nil {
+		credited = *receiver
+		data = *receiver
+	}
+	ownerID := owner.NewID()
+	ownerID.SetScriptHash(credited)
+
+	before, err := GetBalance(ctx, cli, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	gas, err := rpc.NativeHash(nativenames.Gas)
+	if err != nil {
+		return nil, err
+	}
+	p, err := rpc.Invoke(ctx, acc, func(b *wallet2.TxBuilder) {
+		b.Call(gas, "transfer", acc.Contract.ScriptHash(), contract, fixed8, data)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't transfer GAS to NeoFS: %w", err)
+	}
+	return complete(ctx, cli, rpc, ownerID, p.Tx.Hash(), before, func(b *Balance) bool {
+		return b.Convert(before.Precision) > before.Value
+	})
+}
+
+// Withdraw asks the NeoFS contract to return amount of GAS to acc, then waits for the NeoFS balance to drop.
+// The contract only pays out whole GAS, and charges a withdrawal fee in GAS on the main chain as well
+func Withdraw(ctx context.Context, cli *client.Client, rpc *wallet2.RPCClient, acc *wallet.Account, contract util.Uint160, amount wallet2.Amount) (*Result, error) {
+	if _, err := gasUnits(amount); err != nil {
+		return nil, err
+	}
+	whole, err := amount.Rescale(0)
+	if err != nil {
+		return nil, fmt.Errorf("NeoFS withdraws whole GAS only: %w", err)
+	}
+	gasAmount, _ := whole.Int64()
+	user := acc.Contract.ScriptHash()
+	ownerID := owner.NewID()
+	ownerID.SetScriptHash(user)
+
+	before, err := GetBalance(ctx, cli, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if before.Amount().Cmp(amount) < 0 {
+		return nil, fmt.Errorf("balance of %s GAS is less than %s", before, amount)
+	}
+	gas, err := rpc.NativeHash(nativenames.Gas)
+	if err != nil {
+		return nil, err
+	}
+
+	// the contract takes its fee with a GAS transfer, so the witness has to reach the GAS contract too
+	sender := wallet2.TxSigner{
+		Account:          acc,
+		Scopes:           transaction.CalledByEntry | transaction.CustomContracts,
+		AllowedContracts: []util.Uint160{contract, gas},
+	}
+	p, err := rpc.InvokeAs(ctx, sender, func(b *wallet2.TxBuilder) {
+		b.Call(contract, "withdraw", user, gasAmount)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't send withdraw transaction: %w", err)
+	}
+	return complete(ctx, cli, rpc, ownerID, p.Tx.Hash(), before, func(b *Balance) bool {
+		return b.Convert(before.Precision) < before.Value
+	})
+}
+
+// gasUnits checks amount is a positive GAS amount and returns it in fixed8 units
+func gasUnits(amount wallet2.Amount) (int64, error) {
+	if amount.Sign() <= 0 {
+		return 0, errors.New("amount must be positive")
+	}
+	fixed8, err := amount.Rescale(GAS_DECIMALS)
+	if err != nil {
+		return 0, err
+	}
+	v, ok := fixed8.Int64()
+	if !ok {
+		return 0, fmt.Errorf("amount %s is too large", amount)
+	}
+	return v, nil
+}
+
+func complete(ctx context.Context, cli *client.Client, rpc *wallet2.RPCClient, ownerID *owner.ID, txHash util.Uint256, before *Balance, done func(*Balance) bool) (*Result, error) {
+	res := &Result{TxHash: txHash, Before: before}
+	gasUsed, err := WaitForTransaction(ctx, rpc, txHash)
+	if err != nil {
+		return res, err
+	}
+	res.GasUsed = gasUsed
+	res.After, err = WaitForBalance(ctx, cli, ownerID, done)
+	return res, err
+}
+
+// WaitForTransaction polls until the transaction is in a block and returns the GAS it consumed.
+// It fails if the transaction faulted
+func WaitForTransaction(ctx context.Context, rpc *wallet2.RPCClient, txHash util.Uint256) (int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	tracker := wallet2.NewTracker(rpc)
+	done := tracker.WatchChan(txHash, 0)
+	go tracker.Run(ctx)
+	r := <-done
+	if r.Err != nil {
+		return 0, r.Err
+	}
+	log := r.Log
+	if err := wallet2.ExecutionError(log); err != nil {
+		if len(log.Executions) != 0 {
+			return log.Executions[0].GasConsumed, err
+		}
+		return 0, err
+	}
+	return log.Executions[0].GasConsumed, nil
+}
+
+// WaitForBalance polls the NeoFS balance until done returns true. The side chain only sees main chain
+// deposits and withdrawals once the inner ring has processed them, which takes a few blocks
+func WaitForBalance(ctx context.Context, cli *client.Client, ownerID *owner.ID, done func(*Balance) bool) (*Balance, error) {
+	ticker := time.NewTicker(DEFAULT_POLL_INTERVAL)
+	defer ticker.Stop()
+	for {
+		b, err := GetBalance(ctx, cli, ownerID)
+		if err == nil && done(b) {
+			return b, nil
+		}
+		select {
+		case <-ctx.Done():
+			return b, fmt.Errorf("NeoFS balance not updated: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
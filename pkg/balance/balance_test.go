@@ -0,0 +1,69 @@
+package balance_test
+
+import (
+	"testing"
+
+	"github.com/configwizard/gaspump-api/pkg/balance"
+	wallet2 "github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neofs-sdk-go/accounting"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalance(t *testing.T) {
+	d := accounting.NewDecimal()
+	d.SetValue(12_500_000_000_000)
+	d.SetPrecision(12)
+	b := balance.NewBalance(d)
+	assert.Equal(t, "12.5", b.String())
+	assert.Equal(t, int64(1_250_000_000), b.GAS())
+	assert.Equal(t, int64(12), b.Convert(0))
+
+	for _, c := range []struct {
+		value     int64
+		precision uint32
+		expected  string
+	}{
+		{0, 12, "0"},
+		{1, 12, "0.000000000001"},
+		{-150_000_000, 8, "-1.5"},
+		{42, 0, "42"},
+		{100_000_000, 8, "1"},
+	} {
+		b := balance.Balance{Value: c.value, Precision: c.precision}
+		assert.Equal(t, c.expected, b.String())
+	}
+
+	fixed8 := balance.Balance{Value: 3, Precision: 8}
+	assert.Equal(t, int64(30_000), fixed8.Convert(12))
+}
+
+func TestNeoFSContract(t *testing.T) {
+	h, err := balance.NeoFSContract(wallet2.RPC_TESTNET)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, balance.NEOFS_CONTRACT_TESTNET, wallet2.Uint160ToString(h))
+	_, err = balance.NeoFSContract(wallet2.RPC_MAINNET)
+	assert.Nil(t, err, "error not nil")
+	_, err = balance.NeoFSContract("http://localhost:30333")
+	assert.NotNil(t, err, "unknown network accepted")
+}
+
+func TestKindOf(t *testing.T) {
+	contract, err := balance.NeoFSContract(wallet2.RPC_TESTNET)
+	assert.Nil(t, err, "error not nil")
+	gas, neo := util.Uint160{6}, util.Uint160{5}
+	neofs := wallet2.Uint160ToString(contract)
+	other := wallet2.Uint160ToString(util.Uint160{9})
+
+	for _, c := range []struct {
+		entry    wallet2.Nep17HistoryEntry
+		expected balance.ActivityKind
+	}{
+		{wallet2.Nep17HistoryEntry{Token: gas, Counterparty: neofs, Direction: wallet2.TRANSFER_SENT}, balance.ACTIVITY_DEPOSIT},
+		{wallet2.Nep17HistoryEntry{Token: gas, Counterparty: neofs, Direction: wallet2.TRANSFER_RECEIVED}, balance.ACTIVITY_WITHDRAWAL},
+		{wallet2.Nep17HistoryEntry{Token: gas, Counterparty: other, Direction: wallet2.TRANSFER_SENT}, balance.ACTIVITY_SENT},
+		{wallet2.Nep17HistoryEntry{Token: neo, Counterparty: neofs, Direction: wallet2.TRANSFER_RECEIVED}, balance.ACTIVITY_RECEIVED},
+	} {
+		assert.Equal(t, c.expected, balance.KindOf(c.entry, gas, contract))
+	}
+}
@@ -1,7 +1,8 @@
 package client
 
 import (
-	"crypto/ecdsa"
+	"context"
+	"github.com/configwizard/gaspump-api/pkg/signer"
 	"github.com/nspcc-dev/neofs-api-go/v2/acl"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
 	"github.com/nspcc-dev/neofs-sdk-go/eacl"
@@ -10,7 +11,7 @@ import (
 )
 
 //duration = 30
-func ExampleBearerToken(duration uint64, containerID *cid.ID, tokenReceiver *owner.ID, currentEpoch uint64, toWhom *eacl.Target, t *eacl.Table, containerOwnerKey *ecdsa.PrivateKey)(*token.BearerToken, error) {
+func ExampleBearerToken(ctx context.Context, duration uint64, containerID *cid.ID, tokenReceiver *owner.ID, currentEpoch uint64, toWhom *eacl.Target, t *eacl.Table, containerOwner signer.Signer)(*token.BearerToken, error) {
 	bt := token.NewBearerToken()
 	bt.SetOwner(tokenReceiver)
 
@@ -43,16 +44,17 @@ func ExampleBearerToken(duration uint64, containerID *cid.ID, tokenReceiver *own
 	lt.SetExp(currentEpoch + duration)
 	bt.SetLifetime(lt.GetExp(), lt.GetNbf(), lt.GetIat())
 
-	err := bt.SignToken(containerOwnerKey)
+	err := SignBearerToken(ctx, containerOwner, bt)
 	return bt, err
 }
-func NewBearerToken(tokenReceiver *owner.ID, expire uint64, eaclTable eacl.Table, sign bool, containerOwnerKey *ecdsa.PrivateKey) (*token.BearerToken, error){
+// NewBearerToken creates a token for tokenReceiver. It is signed by containerOwner, or left unsigned if containerOwner is nil
+func NewBearerToken(ctx context.Context, tokenReceiver *owner.ID, expire uint64, eaclTable eacl.Table, containerOwner signer.Signer) (*token.BearerToken, error){
 	btoken :=  token.NewBearerToken()
 	btoken.SetLifetime(expire, 0, 0)
 	btoken.SetOwner(tokenReceiver)
 	btoken.SetEACLTable(&eaclTable)
-	if sign {
-		if err := btoken.SignToken(containerOwnerKey); err != nil {
+	if containerOwner != nil {
+		if err := SignBearerToken(ctx, containerOwner, btoken); err != nil {
 			return btoken, err
 		}
 	}
@@ -3,7 +3,13 @@ package client
 import (
 	"context"
 	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/configwizard/gaspump-api/pkg/signer"
 	"github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neofs-sdk-go/client"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
 	"github.com/nspcc-dev/neofs-sdk-go/object/address"
@@ -12,110 +18,218 @@ import (
 	"github.com/nspcc-dev/neofs-sdk-go/session"
 )
 
-func CreateSessionWithObjectGetContext(ctx context.Context, cli *client.Client, owner *owner.ID, containerID *cid.ID, expiry uint64, key *ecdsa.PrivateKey) (*session.Token, error) {
-	var prmSessionCreate client.PrmSessionCreate
-	prmSessionCreate.SetExp(expiry)
-	stoken := session.NewToken()
-	res, err := cli.SessionCreate(ctx, prmSessionCreate)
-	if err != nil {
-		return stoken, err
-	}
-	addr := address.NewAddress()
-	addr.SetContainerID(containerID)
+// SessionVerb is an operation a session token can be issued for
+type SessionVerb string
 
-	objectCtx := session.NewObjectContext()
-	objectCtx.ForGet()
-	objectCtx.ApplyTo(addr)
+const (
+	ObjectPut       SessionVerb = "object.put"
+	ObjectGet       SessionVerb = "object.get"
+	ObjectHead      SessionVerb = "object.head"
+	ObjectSearch    SessionVerb = "object.search"
+	ObjectDelete    SessionVerb = "object.delete"
+	ObjectRange     SessionVerb = "object.range"
+	ObjectRangeHash SessionVerb = "object.rangehash"
 
-	stoken.SetSessionKey(res.PublicKey())
-	stoken.SetID(res.ID())
-	stoken.SetExp(expiry)
-	if owner == nil {
-		owner, err = wallet.OwnerIDFromPrivateKey(key)
-		if err != nil {
-			return &session.Token{}, err
+	ContainerPut     SessionVerb = "container.put"
+	ContainerDelete  SessionVerb = "container.delete"
+	ContainerSetEACL SessionVerb = "container.seteacl"
+)
+
+// ObjectVerbs and ContainerVerbs list every operation NeoFS supports sessions for
+var (
+	ObjectVerbs    = []SessionVerb{ObjectPut, ObjectGet, ObjectHead, ObjectSearch, ObjectDelete, ObjectRange, ObjectRangeHash}
+	ContainerVerbs = []SessionVerb{ContainerPut, ContainerDelete, ContainerSetEACL}
+)
+
+// IsObjectVerb is true for verbs that operate on objects rather than containers
+func (v SessionVerb) IsObjectVerb() bool {
+	for _, o := range ObjectVerbs {
+		if o == v {
+			return true
 		}
 	}
-	stoken.SetOwnerID(owner)
-	stoken.SetContext(objectCtx)
+	return false
+}
 
-	err = stoken.Sign(key)
-	if err != nil {
-		return stoken, err
+// SessionScope limits what a session token can be used on.
+// A nil ContainerID is a wildcard, a nil ObjectID covers the whole container
+type SessionScope struct {
+	ContainerID *cid.ID
+	ObjectID    *oid.ID
+}
+
+// WildcardScope applies a token to every container the owner has
+func WildcardScope() SessionScope {
+	return SessionScope{}
+}
+
+// ContainerScope applies a token to a container and every object in it
+func ContainerScope(containerID cid.ID) SessionScope {
+	return SessionScope{ContainerID: &containerID}
+}
+
+// ObjectScope applies a token to a single object
+func ObjectScope(containerID cid.ID, objectID oid.ID) SessionScope {
+	return SessionScope{ContainerID: &containerID, ObjectID: &objectID}
+}
+
+// NewSessionContext builds the token context for a verb and scope, rejecting combinations NeoFS can't honour
+func NewSessionContext(verb SessionVerb, scope SessionScope) (interface{}, error) {
+	if scope.ObjectID != nil && scope.ContainerID == nil {
+		return nil, errors.New("an object scope requires a container")
 	}
-	return stoken, nil
+	if verb.IsObjectVerb() {
+		if scope.ObjectID != nil && (verb == ObjectPut || verb == ObjectSearch) {
+			return nil, fmt.Errorf("%s can't be scoped to a single object", verb)
+		}
+		objectCtx := session.NewObjectContext()
+		switch verb {
+		case ObjectPut:
+			objectCtx.ForPut()
+		case ObjectGet:
+			objectCtx.ForGet()
+		case ObjectHead:
+			objectCtx.ForHead()
+		case ObjectSearch:
+			objectCtx.ForSearch()
+		case ObjectDelete:
+			objectCtx.ForDelete()
+		case ObjectRange:
+			objectCtx.ForRange()
+		case ObjectRangeHash:
+			objectCtx.ForRangeHash()
+		}
+		if scope.ContainerID != nil {
+			addr := address.NewAddress()
+			addr.SetContainerID(scope.ContainerID)
+			if scope.ObjectID != nil {
+				addr.SetObjectID(scope.ObjectID)
+			}
+			objectCtx.ApplyTo(addr)
+		}
+		return objectCtx, nil
+	}
+	if scope.ObjectID != nil {
+		return nil, fmt.Errorf("%s can't be scoped to an object", verb)
+	}
+	cntContext := session.NewContainerContext()
+	switch verb {
+	case ContainerPut:
+		// the container doesn't exist yet, so it can't be named
+		if scope.ContainerID != nil {
+			return nil, errors.New("container put can't be scoped to a container")
+		}
+		cntContext.ForPut()
+	case ContainerDelete:
+		cntContext.ForDelete()
+	case ContainerSetEACL:
+		cntContext.ForSetEACL()
+	default:
+		return nil, fmt.Errorf("unknown session verb %q", verb)
+	}
+	cntContext.ApplyTo(scope.ContainerID)
+	return cntContext, nil
 }
-func CreateSessionWithObjectPutContext(ctx context.Context, cli *client.Client, owner *owner.ID, containerID *cid.ID, expiry uint64, key *ecdsa.PrivateKey) (*session.Token, error) {
+
+// CreateSessionTokens opens a single session with the node and returns a token for each verb, all bound to the same scope
+// and signed by s. If owner is nil it is derived from the signer's key
+func CreateSessionTokens(ctx context.Context, cli *client.Client, owner *owner.ID, verbs []SessionVerb, scope SessionScope, expiry uint64, s signer.Signer) (map[SessionVerb]*session.Token, error) {
+	contexts := make(map[SessionVerb]interface{})
+	for _, v := range verbs {
+		c, err := NewSessionContext(v, scope)
+		if err != nil {
+			return nil, err
+		}
+		contexts[v] = c
+	}
+	if owner == nil {
+		var err error
+		owner, err = wallet.OwnerIDFromPublicKey((*ecdsa.PublicKey)(s.PublicKey()))
+		if err != nil {
+			return nil, err
+		}
+	}
 	var prmSessionCreate client.PrmSessionCreate
 	prmSessionCreate.SetExp(expiry)
-
-	stoken := session.NewToken()
 	res, err := cli.SessionCreate(ctx, prmSessionCreate)
 	if err != nil {
-		return stoken, err
+		return nil, err
 	}
-	addr := address.NewAddress()
-	addr.SetContainerID(containerID)
-
-	objectCtx := session.NewObjectContext()
-	objectCtx.ForPut()
-	objectCtx.ApplyTo(addr)
-
-	stoken.SetSessionKey(res.PublicKey())
-	stoken.SetID(res.ID())
-	stoken.SetExp(expiry)
-	if owner == nil {
-		owner, err = wallet.OwnerIDFromPrivateKey(key)
-		if err != nil {
-			return &session.Token{}, err
+	tokens := make(map[SessionVerb]*session.Token)
+	for v, c := range contexts {
+		stoken := session.NewToken()
+		stoken.SetSessionKey(res.PublicKey())
+		stoken.SetID(res.ID())
+		stoken.SetExp(expiry)
+		stoken.SetOwnerID(owner)
+		stoken.SetContext(c)
+		if err := SignSessionToken(ctx, s, stoken); err != nil {
+			return nil, err
 		}
+		tokens[v] = stoken
 	}
-	stoken.SetOwnerID(owner)
-	stoken.SetContext(objectCtx)
+	return tokens, nil
+}
 
-	err = stoken.Sign(key)
+// CreateSessionToken returns a token for a single verb and scope, signed by s
+func CreateSessionToken(ctx context.Context, cli *client.Client, owner *owner.ID, verb SessionVerb, scope SessionScope, expiry uint64, s signer.Signer) (*session.Token, error) {
+	tokens, err := CreateSessionTokens(ctx, cli, owner, []SessionVerb{verb}, scope, expiry, s)
 	if err != nil {
-		return stoken, err
+		return &session.Token{}, err
 	}
-	return stoken, nil
+	return tokens[verb], nil
 }
-func CreateSessionWithObjectDeleteContext(ctx context.Context, cli *client.Client, owner *owner.ID, objectID oid.ID, containerID cid.ID, expiry uint64, key *ecdsa.PrivateKey) (*session.Token, error) {
-	var prmSessionCreate client.PrmSessionCreate
-	prmSessionCreate.SetExp(expiry)
 
-	stoken := session.NewToken()
-	res, err := cli.SessionCreate(ctx, prmSessionCreate)
+// NewContainerSessionToken creates a container session token without contacting a node. Container sessions are checked
+// against the token alone, so sessionKey is the key of the client that will send the request. This lets a gateway
+// act for an owner whose key it never holds
+func NewContainerSessionToken(ctx context.Context, s signer.Signer, sessionKey *keys.PublicKey, verb SessionVerb, scope SessionScope, expiry uint64) (*session.Token, error) {
+	if verb.IsObjectVerb() {
+		return nil, fmt.Errorf("%s sessions must be opened with a node", verb)
+	}
+	c, err := NewSessionContext(verb, scope)
 	if err != nil {
-		return stoken, err
+		return nil, err
 	}
-	addr := address.NewAddress()
-	addr.SetContainerID(&containerID)
-	addr.SetObjectID(&objectID)
-
-	objectCtx := session.NewObjectContext()
-	objectCtx.ForDelete()
-	objectCtx.ApplyTo(addr)
-
-	stoken.SetSessionKey(res.PublicKey())
-	stoken.SetID(res.ID())
-	stoken.SetExp(expiry)
-	if owner == nil {
-		owner, err = wallet.OwnerIDFromPrivateKey(key)
-		if err != nil {
-			return &session.Token{}, err
-		}
+	owner, err := wallet.OwnerIDFromPublicKey((*ecdsa.PublicKey)(s.PublicKey()))
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
 	}
+	// version 4 UUID
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+	stoken := session.NewToken()
+	stoken.SetID(id)
+	stoken.SetSessionKey(sessionKey.Bytes())
 	stoken.SetOwnerID(owner)
-	stoken.SetContext(objectCtx)
-
-	err = stoken.Sign(key)
-	if err != nil {
-		return stoken, err
+	stoken.SetExp(expiry)
+	stoken.SetContext(c)
+	if err := SignSessionToken(ctx, s, stoken); err != nil {
+		return nil, err
 	}
 	return stoken, nil
 }
 
-//alternative/reference
+// Deprecated: use CreateSessionToken with ObjectGet
+func CreateSessionWithObjectGetContext(ctx context.Context, cli *client.Client, owner *owner.ID, containerID *cid.ID, expiry uint64, key *ecdsa.PrivateKey) (*session.Token, error) {
+	return CreateSessionToken(ctx, cli, owner, ObjectGet, SessionScope{ContainerID: containerID}, expiry, signer.NewKeySigner(key))
+}
+
+// Deprecated: use CreateSessionToken with ObjectPut
+func CreateSessionWithObjectPutContext(ctx context.Context, cli *client.Client, owner *owner.ID, containerID *cid.ID, expiry uint64, key *ecdsa.PrivateKey) (*session.Token, error) {
+	return CreateSessionToken(ctx, cli, owner, ObjectPut, SessionScope{ContainerID: containerID}, expiry, signer.NewKeySigner(key))
+}
+
+// Deprecated: use CreateSessionToken with ObjectDelete
+func CreateSessionWithObjectDeleteContext(ctx context.Context, cli *client.Client, owner *owner.ID, objectID oid.ID, containerID cid.ID, expiry uint64, key *ecdsa.PrivateKey) (*session.Token, error) {
+	return CreateSessionToken(ctx, cli, owner, ObjectDelete, ObjectScope(containerID, objectID), expiry, signer.NewKeySigner(key))
+}
+
+// Deprecated: listing containers needs no session token. Use CreateSessionToken for a token bound to a verb and scope
 func CreateSessionForContainerList(ctx context.Context, cli *client.Client, expiry uint64, key *ecdsa.PrivateKey) (*session.Token, error) {
 	create := client.PrmSessionCreate{}
 	create.SetExp(expiry)
@@ -131,51 +245,14 @@ func CreateSessionForContainerList(ctx context.Context, cli *client.Client, expi
 	st.SetOwnerID(id)
 	st.SetID(sessionResponse.ID())
 	st.SetSessionKey(sessionResponse.PublicKey())
-	err = st.Sign(key)
+	err = SignSessionToken(ctx, signer.NewKeySigner(key), st)
 	if err != nil {
 		return &session.Token{}, err
 	}
 	return st, nil
 }
-func CreateSessionWithContainerDeleteContext(ctx context.Context, cli *client.Client, owner *owner.ID, containerID cid.ID, expiry uint64, key *ecdsa.PrivateKey) (*session.Token, error) {
-	var prmSessionCreate client.PrmSessionCreate
-
-	prmSessionCreate.SetExp(expiry)
-
-	res, err := cli.SessionCreate(ctx, prmSessionCreate)
-	if err != nil {
-		return &session.Token{}, err
-	}
-	addr := address.NewAddress()
-	addr.SetContainerID(&containerID)
-
-
-	cntContext := session.NewContainerContext()
-	cntContext.IsForDelete()
-	cntContext.ApplyTo(&containerID)
 
-	objectCtx := session.NewObjectContext()
-	objectCtx.ForPut()
-	objectCtx.ApplyTo(addr)
-
-	stoken := session.NewToken()
-	stoken.SetSessionKey(res.PublicKey())
-	stoken.SetID(res.ID())
-	stoken.SetExp(expiry)
-	if owner == nil {
-		owner, err = wallet.OwnerIDFromPrivateKey(key)
-		if err != nil {
-			return &session.Token{}, err
-		}
-	}
-	stoken.SetOwnerID(owner)
-	stoken.SetContext(cntContext)
-
-	err = stoken.Sign(key)
-	if err != nil {
-		return &session.Token{}, err
-	}
-
-	return stoken, nil
+// Deprecated: use CreateSessionToken with ContainerDelete
+func CreateSessionWithContainerDeleteContext(ctx context.Context, cli *client.Client, owner *owner.ID, containerID cid.ID, expiry uint64, key *ecdsa.PrivateKey) (*session.Token, error) {
+	return CreateSessionToken(ctx, cli, owner, ContainerDelete, ContainerScope(containerID), expiry, signer.NewKeySigner(key))
 }
-
@@ -0,0 +1,43 @@
+package client_test
+
+import (
+	"testing"
+
+	client2 "github.com/configwizard/gaspump-api/pkg/client"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/session"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSessionContext(t *testing.T) {
+	containerID := cid.ID{}
+	assert.Nil(t, containerID.Parse("HNhjKjd864CKBbce3voBMRu9j95rHCtTzHcycUMwuZTx"), "error not nil")
+	objectID := oid.ID{}
+	assert.Nil(t, objectID.Parse("Hw5z3F78HrgmCgUqw8KNkcgtaEcmv66Zxr193Nj1ZSnd"), "error not nil")
+
+	c, err := client2.NewSessionContext(client2.ObjectRange, client2.ObjectScope(containerID, objectID))
+	assert.Nil(t, err, "error not nil")
+	objectCtx, ok := c.(*session.ObjectContext)
+	assert.True(t, ok, "not an object context")
+	assert.True(t, objectCtx.IsForRange())
+	assert.Equal(t, objectID.String(), objectCtx.Address().ObjectID().String())
+
+	c, err = client2.NewSessionContext(client2.ContainerSetEACL, client2.ContainerScope(containerID))
+	assert.Nil(t, err, "error not nil")
+	cntCtx, ok := c.(*session.ContainerContext)
+	assert.True(t, ok, "not a container context")
+	assert.True(t, cntCtx.IsForSetEACL())
+	assert.Equal(t, containerID.String(), cntCtx.Container().String())
+
+	c, err = client2.NewSessionContext(client2.ContainerPut, client2.WildcardScope())
+	assert.Nil(t, err, "error not nil")
+	assert.Nil(t, c.(*session.ContainerContext).Container(), "container put is not a wildcard")
+
+	_, err = client2.NewSessionContext(client2.ObjectSearch, client2.ObjectScope(containerID, objectID))
+	assert.NotNil(t, err, "search scoped to an object was accepted")
+	_, err = client2.NewSessionContext(client2.ContainerPut, client2.ContainerScope(containerID))
+	assert.NotNil(t, err, "container put scoped to a container was accepted")
+	_, err = client2.NewSessionContext(client2.ContainerDelete, client2.ObjectScope(containerID, objectID))
+	assert.NotNil(t, err, "container delete scoped to an object was accepted")
+}
@@ -0,0 +1,228 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/configwizard/gaspump-api/pkg/signer"
+	"github.com/nspcc-dev/neofs-sdk-go/client"
+	apistatus "github.com/nspcc-dev/neofs-sdk-go/client/status"
+	"github.com/nspcc-dev/neofs-sdk-go/owner"
+	"github.com/nspcc-dev/neofs-sdk-go/session"
+)
+
+const (
+	// DEFAULT_SESSION_LIFETIME is how many epochs a managed session token is valid for
+	DEFAULT_SESSION_LIFETIME = 10
+	// DEFAULT_RENEW_BEFORE is how many epochs before expiry a managed token is replaced
+	DEFAULT_RENEW_BEFORE = 2
+	// DEFAULT_EPOCH_REFRESH is how long the manager trusts the last epoch it read from the network
+	DEFAULT_EPOCH_REFRESH = time.Minute
+)
+
+// IsErrSessionExpired checks if err is a NeoFS status saying the session token has expired or the node no longer knows it
+func IsErrSessionExpired(err error) bool {
+	for err != nil {
+		switch err.(type) {
+		case apistatus.SessionTokenExpired, *apistatus.SessionTokenExpired,
+			apistatus.SessionTokenNotFound, *apistatus.SessionTokenNotFound:
+			return true
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
+
+type sessionKey struct {
+	verb      SessionVerb
+	container string
+	object    string
+}
+
+func newSessionKey(verb SessionVerb, scope SessionScope) sessionKey {
+	k := sessionKey{verb: verb}
+	if scope.ContainerID != nil {
+		k.container = scope.ContainerID.String()
+	}
+	if scope.ObjectID != nil {
+		k.object = scope.ObjectID.String()
+	}
+	return k
+}
+
+// sessionEntry has its own lock so a slow SessionCreate for one scope doesn't block the others
+type sessionEntry struct {
+	mu    sync.Mutex
+	token *session.Token
+}
+
+type sessionCreator func(ctx context.Context, verb SessionVerb, scope SessionScope, expiry uint64) (*session.Token, error)
+type epochSource func(ctx context.Context) (uint64, error)
+
+// SessionManager hands out session tokens per verb and scope, reusing them until they are close to expiry.
+// It is safe for concurrent use. The object and container packages don't take a manager: callers pass its tokens in,
+// either from Token or inside Do, which renews an expired token and retries
+type SessionManager struct {
+	lifetime     uint64
+	renewBefore  uint64
+	epochRefresh time.Duration
+
+	create sessionCreator
+	epoch  epochSource
+
+	mu        sync.Mutex
+	entries   map[sessionKey]*sessionEntry
+	lastEpoch uint64
+	epochRead time.Time
+}
+
+// NewSessionManager creates a manager that opens sessions on cli, with tokens signed by s.
+// A lifetime of 0 uses DEFAULT_SESSION_LIFETIME
+func NewSessionManager(cli *client.Client, s signer.Signer, ownerID *owner.ID, lifetime uint64) *SessionManager {
+	return newSessionManager(
+		func(ctx context.Context, verb SessionVerb, scope SessionScope, expiry uint64) (*session.Token, error) {
+			return CreateSessionToken(ctx, cli, ownerID, verb, scope, expiry, s)
+		},
+		func(ctx context.Context) (uint64, error) {
+			info, err := GetNetworkInfo(ctx, cli)
+			if err != nil {
+				return 0, err
+			}
+			return info.CurrentEpoch(), nil
+		},
+		lifetime)
+}
+
+func newSessionManager(create sessionCreator, epoch epochSource, lifetime uint64) *SessionManager {
+	if lifetime == 0 {
+		lifetime = DEFAULT_SESSION_LIFETIME
+	}
+	renewBefore := uint64(DEFAULT_RENEW_BEFORE)
+	if renewBefore >= lifetime {
+		renewBefore = lifetime / 2
+	}
+	return &SessionManager{
+		lifetime:     lifetime,
+		renewBefore:  renewBefore,
+		epochRefresh: DEFAULT_EPOCH_REFRESH,
+		create:       create,
+		epoch:        epoch,
+		entries:      make(map[sessionKey]*sessionEntry),
+	}
+}
+
+// SetRenewBefore sets how many epochs before expiry a token is replaced. It must be less than the lifetime
+func (m *SessionManager) SetRenewBefore(epochs uint64) error {
+	if epochs >= m.lifetime {
+		return errors.New("renewal window must be shorter than the token lifetime")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.renewBefore = epochs
+	return nil
+}
+
+// SetEpochRefresh sets how long a read of the current epoch is reused before asking the network again
+func (m *SessionManager) SetEpochRefresh(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.epochRefresh = d
+}
+
+func (m *SessionManager) currentEpoch(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+	if !m.epochRead.IsZero() && time.Since(m.epochRead) < m.epochRefresh {
+		defer m.mu.Unlock()
+		return m.lastEpoch, nil
+	}
+	m.mu.Unlock()
+
+	epoch, err := m.epoch(ctx)
+	if err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// epochs only move forward, don't let a slow response overwrite a newer one
+	if epoch > m.lastEpoch {
+		m.lastEpoch = epoch
+	}
+	m.epochRead = time.Now()
+	return m.lastEpoch, nil
+}
+
+func (m *SessionManager) entry(k sessionKey) *sessionEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[k]
+	if !ok {
+		e = &sessionEntry{}
+		m.entries[k] = e
+	}
+	return e
+}
+
+// Token returns a cached token for the verb and scope, opening a new session if there isn't one or it is about to expire
+func (m *SessionManager) Token(ctx context.Context, verb SessionVerb, scope SessionScope) (*session.Token, error) {
+	if _, err := NewSessionContext(verb, scope); err != nil {
+		return nil, err
+	}
+	epoch, err := m.currentEpoch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	renewBefore := m.renewBefore
+	m.mu.Unlock()
+
+	e := m.entry(newSessionKey(verb, scope))
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.token != nil && epoch+renewBefore < e.token.Exp() {
+		return e.token, nil
+	}
+	token, err := m.create(ctx, verb, scope, epoch+m.lifetime)
+	if err != nil {
+		return nil, err
+	}
+	e.token = token
+	return token, nil
+}
+
+// Invalidate drops the cached token for the verb and scope so the next call opens a new session
+func (m *SessionManager) Invalidate(verb SessionVerb, scope SessionScope) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, newSessionKey(verb, scope))
+}
+
+// Clear drops every cached token
+func (m *SessionManager) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[sessionKey]*sessionEntry)
+}
+
+// Do runs fn with a managed token. If the node reports the session as expired, the token is
+// replaced and fn is retried once
+func (m *SessionManager) Do(ctx context.Context, verb SessionVerb, scope SessionScope, fn func(sessionToken *session.Token) error) error {
+	token, err := m.Token(ctx, verb, scope)
+	if err != nil {
+		return err
+	}
+	err = fn(token)
+	if !IsErrSessionExpired(err) {
+		return err
+	}
+	m.Invalidate(verb, scope)
+	// the cached epoch is clearly behind the node's, so read it again
+	m.mu.Lock()
+	m.epochRead = time.Time{}
+	m.mu.Unlock()
+	if token, err = m.Token(ctx, verb, scope); err != nil {
+		return err
+	}
+	return fn(token)
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	apistatus "github.com/nspcc-dev/neofs-sdk-go/client/status"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/session"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeNetwork struct {
+	mu      sync.Mutex
+	epoch   uint64
+	created int
+}
+
+func (f *fakeNetwork) create(_ context.Context, _ SessionVerb, _ SessionScope, expiry uint64) (*session.Token, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.created++
+	st := session.NewToken()
+	st.SetExp(expiry)
+	return st, nil
+}
+
+func (f *fakeNetwork) currentEpoch(_ context.Context) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.epoch, nil
+}
+
+func TestSessionManagerRenewal(t *testing.T) {
+	network := &fakeNetwork{epoch: 100}
+	m := newSessionManager(network.create, network.currentEpoch, 10)
+	m.SetEpochRefresh(0)
+	containerID := cid.ID{}
+	assert.Nil(t, containerID.Parse("HNhjKjd864CKBbce3voBMRu9j95rHCtTzHcycUMwuZTx"), "error not nil")
+	scope := ContainerScope(containerID)
+
+	first, err := m.Token(context.Background(), ObjectPut, scope)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, uint64(110), first.Exp())
+	again, err := m.Token(context.Background(), ObjectPut, scope)
+	assert.Nil(t, err, "error not nil")
+	assert.Same(t, first, again)
+
+	_, err = m.Token(context.Background(), ObjectGet, scope)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, 2, network.created, "verbs should not share tokens")
+
+	network.epoch = 108
+	renewed, err := m.Token(context.Background(), ObjectPut, scope)
+	assert.Nil(t, err, "error not nil")
+	assert.NotSame(t, first, renewed)
+	assert.Equal(t, uint64(118), renewed.Exp())
+}
+
+func TestSessionManagerDoRetriesExpired(t *testing.T) {
+	network := &fakeNetwork{epoch: 100}
+	m := newSessionManager(network.create, network.currentEpoch, 0)
+	calls := 0
+	err := m.Do(context.Background(), ContainerPut, WildcardScope(), func(sessionToken *session.Token) error {
+		calls++
+		if calls == 1 {
+			return apistatus.SessionTokenExpired{}
+		}
+		return nil
+	})
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 2, network.created)
+}
+
+func TestSessionManagerConcurrent(t *testing.T) {
+	network := &fakeNetwork{epoch: 100}
+	m := newSessionManager(network.create, network.currentEpoch, 0)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := m.Token(context.Background(), ObjectSearch, WildcardScope())
+			assert.Nil(t, err, "error not nil")
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, network.created)
+}
@@ -3,9 +3,7 @@ package client
 import (
 	"context"
 	"crypto/ecdsa"
-	"crypto/elliptic"
-	"crypto/rand"
-	"crypto/sha512"
+	"github.com/configwizard/gaspump-api/pkg/signer"
 	"github.com/configwizard/gaspump-api/pkg/wallet"
 	"github.com/nspcc-dev/neofs-api-go/v2/acl"
 	"github.com/nspcc-dev/neofs-api-go/v2/refs"
@@ -68,17 +66,51 @@ func GenerateUnsignedBearerToken(ctx context.Context, cli *client.Client, table
 
 // must be done by the container owner
 func SignBytesOnBehalf(binaryData []byte, privateKey *ecdsa.PrivateKey) ([]byte, error ){
-
-	// this sign procedure is copy-pasted from
+	// same output as
 	// https://github.com/nspcc-dev/neofs-sdk-go/blob/40aaaafc73a6b90583b373f231b6e8f0523bf59f/util/signature/options.go#L28
-	h := sha512.Sum512(binaryData)
-	x, y, err := ecdsa.Sign(rand.Reader, privateKey, h[:])
+	// without going through elliptic.Marshal, which rejects r and s as they aren't a point on the curve
+	return signer.NewKeySigner(privateKey).Sign(context.Background(), signer.SchemeSHA512, binaryData)
+}
+
+// SignBearerToken signs the token body with s, in the signer's preferred scheme
+func SignBearerToken(ctx context.Context, s signer.Signer, bearerToken *token.BearerToken) error {
+	raw := bearerToken.ToV2()
+	binaryData, err := raw.GetBody().StableMarshal(nil)
+	if err != nil {
+		return err
+	}
+	signature, err := signWith(signer.WithPayloadType(ctx, signer.PayloadBearerToken), s, binaryData)
+	if err != nil {
+		return err
+	}
+	raw.SetSignature(signature)
+	return nil
+}
+
+// SignSessionToken signs the token body with s, in the signer's preferred scheme
+func SignSessionToken(ctx context.Context, s signer.Signer, sessionToken *session.Token) error {
+	raw := sessionToken.ToV2()
+	binaryData, err := raw.GetBody().StableMarshal(nil)
+	if err != nil {
+		return err
+	}
+	signature, err := signWith(signer.WithPayloadType(ctx, signer.PayloadSessionToken), s, binaryData)
+	if err != nil {
+		return err
+	}
+	raw.SetSignature(signature)
+	return nil
+}
+
+func signWith(ctx context.Context, s signer.Signer, binaryData []byte) (*refs.Signature, error) {
+	scheme := signer.DefaultScheme(s)
+	sig, err := s.Sign(ctx, scheme, binaryData)
 	if err != nil {
 		return nil, err
 	}
-	//the signed bytes
-	return elliptic.Marshal(elliptic.P256(), x, y), nil
+	return signer.NewRefsSignature(s.PublicKey().Bytes(), sig, scheme)
 }
+
 // ReceiveSignedBearerToken takes the raw signed token and reattaches it to a token that the gateway can then use
 // 	ownerPublicKey is the 33 byte public key from wallet provider
 //	publicKey := elliptic.Marshal(ownerPublicKey, ownerPublicKey.X, ownerPublicKey.Y)
@@ -107,3 +139,78 @@ func ReceiveSignedSessionToken(rawSessionToken session2.SessionToken, ownerPubli
 	// We can convert RAW protobuf structure back to SDK structure.
 	return signedSessionToken
 }
+
+// AttachBearerTokenSignature verifies signature against the token body and attaches it, keeping whatever scheme it declares
+func AttachBearerTokenSignature(rawBearerToken acl.BearerToken, signature *refs.Signature) (*token.BearerToken, error) {
+	binaryData, err := rawBearerToken.GetBody().StableMarshal(nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := signer.VerifyRefsSignature(signature, binaryData); err != nil {
+		return nil, err
+	}
+	rawBearerToken.SetSignature(signature)
+	return token.NewBearerTokenFromV2(&rawBearerToken), nil
+}
+
+// AttachSessionTokenSignature verifies signature against the token body and attaches it, keeping whatever scheme it declares
+func AttachSessionTokenSignature(rawSessionToken session2.SessionToken, signature *refs.Signature) (*session.Token, error) {
+	binaryData, err := rawSessionToken.GetBody().StableMarshal(nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := signer.VerifyRefsSignature(signature, binaryData); err != nil {
+		return nil, err
+	}
+	rawSessionToken.SetSignature(signature)
+	return session.NewTokenFromV2(&rawSessionToken), nil
+}
+
+// ReceiveWalletConnectSignedBearerToken attaches a signature from a NeoLine/O3 style wallet.
+// The wallet must have been asked to signMessage signer.WalletConnectMessage(binaryData);
+// signatureHex and saltHex are the data and salt fields it returns.
+// Only nodes that understand the wallet connect scheme will accept the token
+func ReceiveWalletConnectSignedBearerToken(rawBearerToken acl.BearerToken, ownerPublicKey []byte, signatureHex, saltHex string) (*token.BearerToken, error) {
+	signature, err := walletConnectSignature(ownerPublicKey, signatureHex, saltHex)
+	if err != nil {
+		return nil, err
+	}
+	return AttachBearerTokenSignature(rawBearerToken, signature)
+}
+
+// ReceiveWalletConnectSignedSessionToken is ReceiveWalletConnectSignedBearerToken for session tokens
+func ReceiveWalletConnectSignedSessionToken(rawSessionToken session2.SessionToken, ownerPublicKey []byte, signatureHex, saltHex string) (*session.Token, error) {
+	signature, err := walletConnectSignature(ownerPublicKey, signatureHex, saltHex)
+	if err != nil {
+		return nil, err
+	}
+	return AttachSessionTokenSignature(rawSessionToken, signature)
+}
+
+func walletConnectSignature(ownerPublicKey []byte, signatureHex, saltHex string) (*refs.Signature, error) {
+	sig, err := signer.WalletConnectSignature(signatureHex, saltHex)
+	if err != nil {
+		return nil, err
+	}
+	return signer.NewRefsSignature(ownerPublicKey, sig, signer.SchemeWalletConnect)
+}
+
+// VerifyBearerTokenSignature checks a bearer token's signature under any supported scheme, including wallet connect
+func VerifyBearerTokenSignature(bearerToken *token.BearerToken) error {
+	raw := bearerToken.ToV2()
+	binaryData, err := raw.GetBody().StableMarshal(nil)
+	if err != nil {
+		return err
+	}
+	return signer.VerifyRefsSignature(raw.GetSignature(), binaryData)
+}
+
+// VerifySessionTokenSignature checks a session token's signature under any supported scheme, including wallet connect
+func VerifySessionTokenSignature(sessionToken *session.Token) error {
+	raw := sessionToken.ToV2()
+	binaryData, err := raw.GetBody().StableMarshal(nil)
+	if err != nil {
+		return err
+	}
+	return signer.VerifyRefsSignature(raw.GetSignature(), binaryData)
+}
@@ -0,0 +1,41 @@
+package client_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	client2 "github.com/configwizard/gaspump-api/pkg/client"
+	"github.com/configwizard/gaspump-api/pkg/signer"
+	"github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neofs-sdk-go/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReceiveWalletConnectSignedBearerToken(t *testing.T) {
+	key, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	ownerID, err := wallet.OwnerIDFromPublicKey(&key.PrivateKey.PublicKey)
+	assert.Nil(t, err, "error not nil")
+	bearerToken := token.NewBearerToken()
+	bearerToken.SetOwner(ownerID)
+	bearerToken.SetLifetime(110, 100, 100)
+	raw := bearerToken.ToV2()
+	binaryData, err := raw.GetBody().StableMarshal(nil)
+	assert.Nil(t, err, "error not nil")
+
+	// what a browser wallet hands back from signMessage(WalletConnectMessage(binaryData))
+	sig, err := signer.Sign(key, signer.SchemeWalletConnect, binaryData)
+	assert.Nil(t, err, "error not nil")
+	signatureHex, saltHex := hex.EncodeToString(sig[:64]), hex.EncodeToString(sig[64:])
+
+	signed, err := client2.ReceiveWalletConnectSignedBearerToken(*raw, key.PublicKey().Bytes(), signatureHex, saltHex)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, signer.ECDSA_RFC6979_SHA256_WALLET_CONNECT, signed.ToV2().GetSignature().GetScheme())
+	assert.Nil(t, client2.VerifyBearerTokenSignature(signed), "signature invalid")
+
+	other, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	_, err = client2.ReceiveWalletConnectSignedBearerToken(*raw, other.PublicKey().Bytes(), signatureHex, saltHex)
+	assert.NotNil(t, err, "signature accepted for the wrong key")
+}
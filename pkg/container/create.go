@@ -3,54 +3,258 @@ package container
 import (
 	"context"
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	client2 "github.com/configwizard/gaspump-api/pkg/client"
+	"github.com/configwizard/gaspump-api/pkg/nns"
+	policy2 "github.com/configwizard/gaspump-api/pkg/policy"
+	"github.com/configwizard/gaspump-api/pkg/signer"
 	"github.com/configwizard/gaspump-api/pkg/wallet"
 
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	v2container "github.com/nspcc-dev/neofs-api-go/v2/container"
 	"github.com/nspcc-dev/neofs-sdk-go/acl"
 	"github.com/nspcc-dev/neofs-sdk-go/client"
 	"github.com/nspcc-dev/neofs-sdk-go/container"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/eacl"
+	"github.com/nspcc-dev/neofs-sdk-go/netmap"
+	"github.com/nspcc-dev/neofs-sdk-go/owner"
 	"github.com/nspcc-dev/neofs-sdk-go/policy"
+	"github.com/nspcc-dev/neofs-sdk-go/session"
+	subnetid "github.com/nspcc-dev/neofs-sdk-go/subnet/id"
 )
 
-func Create(ctx context.Context, cli *client.Client, key *ecdsa.PrivateKey, placementPolicy string, customACL acl.BasicACL, attributes []*container.Attribute) (*cid.ID, error) {
-	// Put method requires Container structure.
-	// Container must contain at least:
-	//   - Owner
-	//   - Basic ACL
-	//   - Placement policy
+const (
+	// CONTAINER_POLL_INTERVAL is how often a new container is looked for while waiting for it
+	CONTAINER_POLL_INTERVAL = time.Second
+	// CONTAINER_WAIT_TIMEOUT is how long Create waits for the container to be persisted in the side chain
+	CONTAINER_WAIT_TIMEOUT = time.Minute
+)
 
-	// Read more about placement policy syntax in specification:
+// CreateContainerParams describes a new container. Only PlacementPolicy is required
+type CreateContainerParams struct {
+	// PlacementPolicy in the policy language, e.g. REP 2, or built with policy.NewBuilder. See
 	// https://github.com/nspcc-dev/neofs-spec/blob/master/01-arch/02-policy.md
-	// https://github.com/nspcc-dev/neofs-api/blob/master/netmap/types.proto
-	//	const placementPolicy = `REP 2 IN X
-	//CBF 2
-	//SELECT 2 FROM * AS X
-	//`
-	//	customACL := acl.EACLReadOnlyBasicRule
-	containerPolicy, err := policy.Parse(placementPolicy)
+	PlacementPolicy string
+	// Subnet to store the container in, the zero subnet if nil
+	Subnet *subnetid.ID
+	// Netmap, e.g. from netmap.Explorer Snapshot, has the policy checked against its online nodes before the container is put, so a policy
+	// no nodes can satisfy fails here rather than leaving an unusable container. Not checked if nil
+	Netmap []netmap.NodeInfo
+	// BasicACL of the container. Zero is acl.PrivateBasicRule, use one of the EACL rules to set an extended ACL
+	BasicACL acl.BasicACL
+	// Name is the human readable Name attribute
+	Name string
+	// NNSName registers the container as NNSName.NNSZone in NNS, NNSZone defaults to the container zone.
+	// If Resolver is set the name is checked to be free first
+	NNSName  string
+	NNSZone  string
+	Resolver *nns.Resolver
+	// Timestamp is the creation time attribute, now if zero
+	Timestamp time.Time
+	// Attributes are added as they are, the fields above take precedence
+	Attributes map[string]string
+	// ClientKey is the key cli signs requests with. Without a SessionToken it is required: if it isn't the signer's
+	// key, Create has the signer issue the session tokens that let the client act for it
+	ClientKey *keys.PublicKey
+	// SessionToken creates the container on behalf of the token's owner, see client.NewContainerSessionToken
+	SessionToken *session.Token
+	// EACL builds the extended ACL set once the container exists, e.g. with the templates of the eacl package.
+	// It needs a BasicACL that allows one. EACLSessionToken signs it on behalf of the owner, like SessionToken
+	EACL             func(id cid.ID) eacl.Table
+	EACLSessionToken *session.Token
+	// Wait returns only once the container is persisted, up to CONTAINER_WAIT_TIMEOUT. Setting EACL implies it
+	Wait bool
+}
+
+// Create puts a container owned by s, or by the owner of p.SessionToken in which case s may be nil.
+// The requests are signed by cli's key, which acts for s through session tokens if it isn't s's key
+func Create(ctx context.Context, cli *client.Client, s signer.Signer, p CreateContainerParams) (*cid.ID, error) {
+	containerPolicy, err := policy.Parse(p.PlacementPolicy)
 	if err != nil {
 		return nil, fmt.Errorf("can't parse placement policy: %w", err)
 	}
-	ownerID, err := wallet.OwnerIDFromPublicKey(&key.PublicKey)
-	// Step 1: create container
-	//containerPolicy, _ := policy.Parse("REP 2")
+	if p.Subnet != nil {
+		containerPolicy.SetSubnetID(p.Subnet)
+	}
+	if p.Netmap != nil {
+		if err := policy2.Validate(containerPolicy, p.Netmap); err != nil {
+			return nil, err
+		}
+	}
+	if p.delegated(s) {
+		info, err := client2.GetNetworkInfo(ctx, cli)
+		if err != nil {
+			return nil, err
+		}
+		if p, err = p.delegate(ctx, s, info.CurrentEpoch()); err != nil {
+			return nil, err
+		}
+	}
+	ownerID, err := p.owner(s)
+	if err != nil {
+		return nil, err
+	}
+	basicACL := p.BasicACL
+	if basicACL == 0 {
+		basicACL = acl.PrivateBasicRule
+	}
+
+	attributes, err := p.attributes(ctx)
+	if err != nil {
+		return nil, err
+	}
 	cnr := container.New(
 		container.WithPolicy(containerPolicy),
 		container.WithOwnerID(ownerID),
-		container.WithCustomBasicACL(customACL),
+		container.WithCustomBasicACL(basicACL),
 	)
-	//cnr.SetSessionToken()
+	if p.SessionToken != nil {
+		cnr.SetSessionToken(p.SessionToken)
+	}
 	cnr.SetAttributes(attributes)
 
 	var prmContainerPut client.PrmContainerPut
 	prmContainerPut.SetContainer(*cnr)
-
 	cnrResponse, err := cli.ContainerPut(ctx, prmContainerPut)
 	if err != nil {
-		return &cid.ID{}, err
+		return nil, fmt.Errorf("can't create container: %w", err)
 	}
 	containerID := cnrResponse.ID()
 
+	if p.Wait || p.EACL != nil {
+		waitCtx, cancel := context.WithTimeout(ctx, CONTAINER_WAIT_TIMEOUT)
+		defer cancel()
+		if err := Await(waitCtx, cli, *containerID); err != nil {
+			return containerID, err
+		}
+	}
+	if p.EACL != nil {
+		table := p.EACL(*containerID)
+		table.SetCID(containerID)
+		if p.EACLSessionToken != nil {
+			table.SetSessionToken(p.EACLSessionToken)
+		}
+		if err := SetEACLOnContainer(ctx, cli, *containerID, table); err != nil {
+			return containerID, err
+		}
+	}
 	return containerID, nil
 }
+
+// delegated reports whether the client key has to act for s, which it can only do with session tokens
+func (p CreateContainerParams) delegated(s signer.Signer) bool {
+	return p.SessionToken == nil && s != nil && p.ClientKey != nil && !p.ClientKey.Equal(s.PublicKey())
+}
+
+// delegate has s issue the session tokens that let the client key put the container and set its eACL for s
+func (p CreateContainerParams) delegate(ctx context.Context, s signer.Signer, epoch uint64) (CreateContainerParams, error) {
+	expiry := epoch + client2.DEFAULT_SESSION_LIFETIME
+	var err error
+	p.SessionToken, err = client2.NewContainerSessionToken(ctx, s, p.ClientKey, client2.ContainerPut, client2.WildcardScope(), expiry)
+	if err != nil {
+		return p, fmt.Errorf("can't issue container session token: %w", err)
+	}
+	if p.EACL != nil && p.EACLSessionToken == nil {
+		// the container ID isn't known yet, so the token covers every container of s
+		p.EACLSessionToken, err = client2.NewContainerSessionToken(ctx, s, p.ClientKey, client2.ContainerSetEACL, client2.WildcardScope(), expiry)
+		if err != nil {
+			return p, fmt.Errorf("can't issue eACL session token: %w", err)
+		}
+	}
+	return p, nil
+}
+
+// owner is the owner of the session token if there is one, otherwise the owner of s's key, which then has to be the
+// client key
+func (p CreateContainerParams) owner(s signer.Signer) (*owner.ID, error) {
+	var ownerID *owner.ID
+	if p.SessionToken != nil {
+		ownerID = p.SessionToken.OwnerID()
+	} else if s != nil {
+		if p.ClientKey == nil {
+			return nil, errors.New("set ClientKey to the key the client signs with, so it can be checked against the signer")
+		}
+		if !p.ClientKey.Equal(s.PublicKey()) {
+			return nil, errors.New("the signer is not the client key and there is no session token for the client")
+		}
+		var err error
+		ownerID, err = wallet.OwnerIDFromPublicKey((*ecdsa.PublicKey)(s.PublicKey()))
+		if err != nil {
+			return nil, fmt.Errorf("can't retrieve owner ID: %w", err)
+		}
+	}
+	if ownerID == nil {
+		return nil, errors.New("container has no owner, pass a signer or a session token")
+	}
+	return ownerID, nil
+}
+
+// attributes puts together the container attributes in a stable order
+func (p CreateContainerParams) attributes(ctx context.Context) ([]*container.Attribute, error) {
+	values := make(map[string]string, len(p.Attributes)+4)
+	for k, v := range p.Attributes {
+		values[k] = v
+	}
+	if p.Name != "" {
+		values[container.AttributeName] = p.Name
+	}
+	if p.NNSName != "" {
+		delete(values, v2container.SysAttributeName)
+		delete(values, v2container.SysAttributeZone)
+	}
+	timestamp := p.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	values[container.AttributeTimestamp] = strconv.FormatInt(timestamp.Unix(), 10)
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attributes := make([]*container.Attribute, 0, len(keys)+2)
+	for _, k := range keys {
+		a := container.NewAttribute()
+		a.SetKey(k)
+		a.SetValue(values[k])
+		attributes = append(attributes, a)
+	}
+
+	if p.NNSName != "" {
+		var nameAttributes []*container.Attribute
+		var err error
+		if p.Resolver != nil {
+			nameAttributes, err = NameAttributes(ctx, p.Resolver, p.NNSName, p.NNSZone)
+		} else {
+			nameAttributes, err = nnsAttributes(p.NNSName, p.NNSZone)
+		}
+		if err != nil {
+			return nil, err
+		}
+		attributes = append(attributes, nameAttributes...)
+	}
+	return attributes, nil
+}
+
+// Await polls until the container can be read from the network, or ctx is done
+func Await(ctx context.Context, cli *client.Client, containerID cid.ID) error {
+	ticker := time.NewTicker(CONTAINER_POLL_INTERVAL)
+	defer ticker.Stop()
+	for {
+		if _, err := Get(ctx, cli, containerID); err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("container %s was not persisted in side chain: %w", containerID, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package container
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/configwizard/gaspump-api/pkg/nns"
+	"github.com/configwizard/gaspump-api/pkg/signer"
+	"github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	v2container "github.com/nspcc-dev/neofs-api-go/v2/container"
+	"github.com/nspcc-dev/neofs-sdk-go/container"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/eacl"
+	"github.com/nspcc-dev/neofs-sdk-go/owner"
+	"github.com/nspcc-dev/neofs-sdk-go/session"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttributes(t *testing.T) {
+	created := time.Unix(1650000000, 0)
+	stamp := strconv.FormatInt(created.Unix(), 10)
+	tests := []struct {
+		name string
+		p    CreateContainerParams
+		want [][2]string
+	}{
+		{
+			name: "timestamp given",
+			p:    CreateContainerParams{Timestamp: created},
+			want: [][2]string{{container.AttributeTimestamp, stamp}},
+		},
+		{
+			name: "name overrides attributes",
+			p: CreateContainerParams{
+				Name:       "photos",
+				Timestamp:  created,
+				Attributes: map[string]string{container.AttributeName: "other", "Owner": "alice"},
+			},
+			want: [][2]string{{container.AttributeName, "photos"}, {"Owner", "alice"}, {container.AttributeTimestamp, stamp}},
+		},
+		{
+			name: "attributes can't override the timestamp",
+			p: CreateContainerParams{
+				Timestamp:  created,
+				Attributes: map[string]string{container.AttributeName: "other", container.AttributeTimestamp: "1"},
+			},
+			want: [][2]string{{container.AttributeName, "other"}, {container.AttributeTimestamp, stamp}},
+		},
+		{
+			name: "system name attributes pass without NNSName",
+			p: CreateContainerParams{
+				Timestamp:  created,
+				Attributes: map[string]string{v2container.SysAttributeName: "raw", v2container.SysAttributeZone: "zone"},
+			},
+			want: [][2]string{{container.AttributeTimestamp, stamp}, {v2container.SysAttributeName, "raw"}, {v2container.SysAttributeZone, "zone"}},
+		},
+		{
+			name: "NNSName replaces system name attributes",
+			p: CreateContainerParams{
+				NNSName:    "photos",
+				Timestamp:  created,
+				Attributes: map[string]string{v2container.SysAttributeName: "raw", v2container.SysAttributeZone: "zone"},
+			},
+			want: [][2]string{
+				{container.AttributeTimestamp, stamp},
+				{v2container.SysAttributeName, "photos"},
+				{v2container.SysAttributeZone, nns.CONTAINER_ZONE},
+			},
+		},
+		{
+			name: "NNSZone",
+			p:    CreateContainerParams{NNSName: "photos", NNSZone: "family", Timestamp: created},
+			want: [][2]string{
+				{container.AttributeTimestamp, stamp},
+				{v2container.SysAttributeName, "photos"},
+				{v2container.SysAttributeZone, "family"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attributes, err := tt.p.attributes(context.Background())
+			assert.Nil(t, err, "error not nil")
+			assert.Equal(t, tt.want, pairs(attributes))
+		})
+	}
+
+	t.Run("timestamp defaults to now", func(t *testing.T) {
+		before := time.Now().Unix()
+		attributes, err := CreateContainerParams{}.attributes(context.Background())
+		assert.Nil(t, err, "error not nil")
+		after := time.Now().Unix()
+		assert.Len(t, attributes, 1)
+		assert.Equal(t, container.AttributeTimestamp, attributes[0].Key())
+		ts, err := strconv.ParseInt(attributes[0].Value(), 10, 64)
+		assert.Nil(t, err, "error not nil")
+		assert.True(t, ts >= before && ts <= after, "timestamp is not the creation time")
+	})
+
+	t.Run("invalid NNSName", func(t *testing.T) {
+		_, err := CreateContainerParams{NNSName: "Not Valid"}.attributes(context.Background())
+		assert.NotNil(t, err, "invalid name accepted")
+	})
+}
+
+func pairs(attributes []*container.Attribute) [][2]string {
+	p := make([][2]string, len(attributes))
+	for i, a := range attributes {
+		p[i] = [2]string{a.Key(), a.Value()}
+	}
+	return p
+}
+
+func TestOwner(t *testing.T) {
+	key, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	s := signer.NewKeySigner(&key.PrivateKey)
+	signerOwner, err := wallet.OwnerIDFromPublicKey((*ecdsa.PublicKey)(key.PublicKey()))
+	assert.Nil(t, err, "error not nil")
+
+	other, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	tokenOwner, err := wallet.OwnerIDFromPublicKey((*ecdsa.PublicKey)(other.PublicKey()))
+	assert.Nil(t, err, "error not nil")
+	sessionToken := session.NewToken()
+	sessionToken.SetOwnerID(tokenOwner)
+
+	tests := []struct {
+		name   string
+		p      CreateContainerParams
+		signer signer.Signer
+		want   *owner.ID
+	}{
+		{name: "signer", p: CreateContainerParams{ClientKey: key.PublicKey()}, signer: s, want: signerOwner},
+		{name: "signer without client key", signer: s},
+		{name: "signer is not the client key", p: CreateContainerParams{ClientKey: other.PublicKey()}, signer: s},
+		{name: "session token", p: CreateContainerParams{SessionToken: sessionToken}, want: tokenOwner},
+		{name: "session token over signer", p: CreateContainerParams{SessionToken: sessionToken}, signer: s, want: tokenOwner},
+		{name: "no owner"},
+		{name: "session token without owner", p: CreateContainerParams{SessionToken: session.NewToken()}, signer: s},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ownerID, err := tt.p.owner(tt.signer)
+			if tt.want == nil {
+				assert.NotNil(t, err, "container without an owner accepted")
+				return
+			}
+			assert.Nil(t, err, "error not nil")
+			assert.True(t, tt.want.Equal(ownerID), "wrong owner")
+		})
+	}
+}
+
+func TestDelegate(t *testing.T) {
+	ownerKey, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	s := signer.NewKeySigner(&ownerKey.PrivateKey)
+	ownerID, err := wallet.OwnerIDFromPublicKey((*ecdsa.PublicKey)(ownerKey.PublicKey()))
+	assert.Nil(t, err, "error not nil")
+	clientKey, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+
+	same := CreateContainerParams{ClientKey: ownerKey.PublicKey()}
+	assert.False(t, same.delegated(s), "client signing as itself needs a session")
+	withToken := CreateContainerParams{ClientKey: clientKey.PublicKey(), SessionToken: session.NewToken()}
+	assert.False(t, withToken.delegated(s), "session token replaced")
+
+	// a remote signer whose key the client doesn't hold
+	p := CreateContainerParams{ClientKey: clientKey.PublicKey(), EACL: func(cid.ID) eacl.Table { return *eacl.NewTable() }}
+	assert.True(t, p.delegated(s), "mismatched client key not delegated")
+	_, err = p.owner(s)
+	assert.NotNil(t, err, "container owned by a key that doesn't sign it")
+
+	p, err = p.delegate(context.Background(), s, 100)
+	assert.Nil(t, err, "error not nil")
+	for _, token := range []*session.Token{p.SessionToken, p.EACLSessionToken} {
+		assert.NotNil(t, token)
+		assert.True(t, token.VerifySignature(), "token not signed by the owner")
+		assert.Equal(t, ownerKey.PublicKey().Bytes(), token.Signature().Key())
+		assert.Equal(t, clientKey.PublicKey().Bytes(), token.SessionKey())
+		assert.True(t, ownerID.Equal(token.OwnerID()), "wrong token owner")
+	}
+	got, err := p.owner(s)
+	assert.Nil(t, err, "error not nil")
+	assert.True(t, ownerID.Equal(got), "container not owned by the signer")
+}
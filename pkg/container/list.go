@@ -4,16 +4,18 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"fmt"
+	"github.com/configwizard/gaspump-api/pkg/signer"
 	"github.com/configwizard/gaspump-api/pkg/wallet"
 
 	"github.com/nspcc-dev/neofs-sdk-go/client"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
 )
 
-func List(ctx context.Context, cli *client.Client, key *ecdsa.PrivateKey) ([]*cid.ID, error) {
+// List returns the containers owned by s
+func List(ctx context.Context, cli *client.Client, s signer.Signer) ([]*cid.ID, error) {
 	// ListContainers method requires Owner ID.
 	// OwnerID is a binary representation of wallets address.
-	ownerID, err := wallet.OwnerIDFromPrivateKey(key)
+	ownerID, err := wallet.OwnerIDFromPublicKey((*ecdsa.PublicKey)(s.PublicKey()))
 	if err != nil {
 		return nil, fmt.Errorf("can't retrieve owner ID: %w", err)
 	}
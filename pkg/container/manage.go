@@ -37,3 +37,14 @@ func SetEACLOnContainer(ctx context.Context, cli *client.Client, containerID cid
 	}
 	return nil
 }
+
+// GetEACL returns the extended ACL currently set on the container
+func GetEACL(ctx context.Context, cli *client.Client, containerID cid.ID) (*eacl.Table, error) {
+	containerEACL := client.PrmContainerEACL{}
+	containerEACL.SetContainer(containerID)
+	resp, err := cli.ContainerEACL(ctx, containerEACL)
+	if err != nil {
+		return nil, fmt.Errorf("can't get extended ACL of %s: %w", containerID, err)
+	}
+	return resp.Table(), nil
+}
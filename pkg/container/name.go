@@ -0,0 +1,49 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/configwizard/gaspump-api/pkg/nns"
+	v2container "github.com/nspcc-dev/neofs-api-go/v2/container"
+	"github.com/nspcc-dev/neofs-sdk-go/container"
+)
+
+// names are single NNS labels, the zone is added when registering
+var containerName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// NameAttributes returns the attributes that have the network register the container as name.zone in NNS
+// when it is created, see CreateContainerParams.NNSName. An empty zone is the container zone.
+// The name is checked to be free first, r must be connected to the NeoFS chain
+func NameAttributes(ctx context.Context, r *nns.Resolver, name, zone string) ([]*container.Attribute, error) {
+	attributes, err := nnsAttributes(name, zone)
+	if err != nil {
+		return nil, err
+	}
+	domain := nns.ContainerDomain(name, attributes[1].Value())
+	available, err := r.IsAvailable(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	if !available {
+		return nil, fmt.Errorf("container name %s is taken", domain)
+	}
+	return attributes, nil
+}
+
+func nnsAttributes(name, zone string) ([]*container.Attribute, error) {
+	if !containerName.MatchString(name) {
+		return nil, fmt.Errorf("invalid container name %q, use lower case letters, digits and hyphens", name)
+	}
+	if zone == "" {
+		zone = nns.CONTAINER_ZONE
+	}
+	nameAttr := container.NewAttribute()
+	nameAttr.SetKey(v2container.SysAttributeName)
+	nameAttr.SetValue(name)
+	zoneAttr := container.NewAttribute()
+	zoneAttr.SetKey(v2container.SysAttributeZone)
+	zoneAttr.SetValue(zone)
+	return []*container.Attribute{nameAttr, zoneAttr}, nil
+}
@@ -0,0 +1,253 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+const (
+	IMPORT_BIG       = "math/big"
+	IMPORT_CONTEXT   = "context"
+	IMPORT_CONTRACT  = "github.com/configwizard/gaspump-api/pkg/contract"
+	IMPORT_KEYS      = "github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	IMPORT_STACKITEM = "github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	IMPORT_UTIL      = "github.com/nspcc-dev/neo-go/pkg/util"
+	IMPORT_WALLET    = "github.com/nspcc-dev/neo-go/pkg/wallet"
+	IMPORT_WALLET2   = "github.com/configwizard/gaspump-api/pkg/wallet"
+)
+
+// names the generated methods use, or that the embedded Contract already has
+var reservedNames = map[string]bool{
+	"ctx": true, "acc": true, "c": true, "res": true, "err": true,
+	"Contract": true, "Hash": true, "Call": true, "TestInvoke": true, "Invoke": true, "New": true,
+}
+
+// LoadManifest reads a contract manifest JSON file, as written by the compiler next to the .nef
+func LoadManifest(path string) (*manifest.Manifest, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m := new(manifest.Manifest)
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// GenerateBindings writes Go source for package pkg wrapping every public method of the contract described by m.
+// Safe methods become calls returning their decoded result, the others send a transaction signed by an account.
+// If hash is zero the contract is not deployed yet and New takes the hash instead
+func GenerateBindings(m *manifest.Manifest, hash util.Uint160, pkg string) ([]byte, error) {
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("invalid package name %q", pkg)
+	}
+	imports := map[string]bool{IMPORT_CONTRACT: true, IMPORT_WALLET2: true}
+	var body strings.Builder
+
+	if hash.Equals(util.Uint160{}) {
+		fmt.Fprintf(&body, "// New binds to the %s contract deployed at hash\n", m.Name)
+		fmt.Fprintf(&body, "func New(rpc *wallet2.RPCClient, hash util.Uint160) *Contract {\n")
+		fmt.Fprintf(&body, "\treturn &Contract{contract.New(rpc, hash)}\n}\n\n")
+	} else {
+		fmt.Fprintf(&body, "// Hash is the script hash of the %s contract, %s\n", m.Name, hash.StringLE())
+		fmt.Fprintf(&body, "var Hash = %#v\n\n", hash)
+		fmt.Fprintf(&body, "func New(rpc *wallet2.RPCClient) *Contract {\n")
+		fmt.Fprintf(&body, "\treturn &Contract{contract.New(rpc, Hash)}\n}\n\n")
+	}
+	imports[IMPORT_UTIL] = true
+
+	methods := make([]manifest.Method, 0, len(m.ABI.Methods))
+	for _, method := range m.ABI.Methods {
+		if !strings.HasPrefix(method.Name, "_") {
+			methods = append(methods, method)
+		}
+	}
+	// overloads are told apart by their number of parameters
+	counts := map[string]int{}
+	for _, method := range methods {
+		counts[exportedName(method.Name)]++
+	}
+	sort.SliceStable(methods, func(i, j int) bool {
+		return methods[i].Name < methods[j].Name
+	})
+	for _, method := range methods {
+		name := exportedName(method.Name)
+		if counts[name] > 1 {
+			name = fmt.Sprintf("%s%d", name, len(method.Parameters))
+		}
+		if reservedNames[name] {
+			name += "Method"
+		}
+		if err := writeMethod(&body, imports, name, method); err != nil {
+			return nil, err
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "// Code generated from the %s contract manifest. DO NOT EDIT.\n\n", m.Name)
+	fmt.Fprintf(&out, "package %s\n\nimport (\n", pkg)
+	paths := make([]string, 0, len(imports))
+	for path := range imports {
+		paths = append(paths, path)
+	}
+	// standard library first
+	sort.Slice(paths, func(i, j int) bool {
+		iStd, jStd := !strings.Contains(paths[i], "."), !strings.Contains(paths[j], ".")
+		if iStd != jStd {
+			return iStd
+		}
+		return paths[i] < paths[j]
+	})
+	for i, path := range paths {
+		if i > 0 && !strings.Contains(paths[i-1], ".") && strings.Contains(path, ".") {
+			out.WriteString("\n")
+		}
+		if path == IMPORT_WALLET2 {
+			fmt.Fprintf(&out, "\twallet2 %q\n", path)
+		} else {
+			fmt.Fprintf(&out, "\t%q\n", path)
+		}
+	}
+	fmt.Fprintf(&out, ")\n\n")
+	fmt.Fprintf(&out, "// Contract calls the methods of %s\n", m.Name)
+	fmt.Fprintf(&out, "type Contract struct {\n\t*contract.Contract\n}\n\n")
+	out.WriteString(body.String())
+
+	src, err := format.Source([]byte(out.String()))
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid code: %w", err)
+	}
+	return src, nil
+}
+
+func writeMethod(w *strings.Builder, imports map[string]bool, name string, method manifest.Method) error {
+	params := make([]string, 0, len(method.Parameters))
+	args := make([]string, 0, len(method.Parameters))
+	seen := map[string]bool{}
+	for i, p := range method.Parameters {
+		typ, err := goType(imports, p.Type, false)
+		if err != nil {
+			return fmt.Errorf("parameter %s of %s: %w", p.Name, method.Name, err)
+		}
+		arg := p.Name
+		if !token.IsIdentifier(arg) || token.IsKeyword(arg) {
+			arg = fmt.Sprintf("arg%d", i)
+		}
+		if reservedNames[arg] || seen[arg] {
+			arg += "Arg"
+		}
+		seen[arg] = true
+		params = append(params, arg+" "+typ)
+		args = append(args, arg)
+	}
+	paramList, argList := "", ""
+	if len(params) > 0 {
+		paramList = ", " + strings.Join(params, ", ")
+		argList = ", " + strings.Join(args, ", ")
+	}
+	imports[IMPORT_CONTEXT] = true
+
+	if !method.Safe {
+		imports[IMPORT_WALLET] = true
+		fmt.Fprintf(w, "// %s sends a transaction calling %s, signed by acc\n", name, method.Name)
+		fmt.Fprintf(w, "func (c *Contract) %s(ctx context.Context, acc *wallet.Account%s) (*wallet2.PreparedTx, error) {\n", name, paramList)
+		fmt.Fprintf(w, "\treturn c.Invoke(ctx, acc, %q%s)\n}\n\n", method.Name, argList)
+		return nil
+	}
+	fmt.Fprintf(w, "// %s calls %s\n", name, method.Name)
+	if method.ReturnType == smartcontract.VoidType {
+		fmt.Fprintf(w, "func (c *Contract) %s(ctx context.Context%s) error {\n", name, paramList)
+		fmt.Fprintf(w, "\treturn c.Call(ctx, %q, nil%s)\n}\n\n", method.Name, argList)
+		return nil
+	}
+	ret, err := goType(imports, method.ReturnType, true)
+	if err != nil {
+		return fmt.Errorf("result of %s: %w", method.Name, err)
+	}
+	fmt.Fprintf(w, "func (c *Contract) %s(ctx context.Context%s) (%s, error) {\n", name, paramList, ret)
+	fmt.Fprintf(w, "\tvar res %s\n", ret)
+	fmt.Fprintf(w, "\terr := c.Call(ctx, %q, &res%s)\n", method.Name, argList)
+	fmt.Fprintf(w, "\treturn res, err\n}\n\n")
+	return nil
+}
+
+// goType is the Go type a parameter is passed as, or a result is decoded into. Arrays, maps and values of any type
+// are returned as stack items since their contents aren't described by the manifest
+func goType(imports map[string]bool, t smartcontract.ParamType, result bool) (string, error) {
+	switch t {
+	case smartcontract.BoolType:
+		return "bool", nil
+	case smartcontract.IntegerType:
+		imports[IMPORT_BIG] = true
+		return "*big.Int", nil
+	case smartcontract.ByteArrayType, smartcontract.SignatureType:
+		return "[]byte", nil
+	case smartcontract.StringType:
+		return "string", nil
+	case smartcontract.Hash160Type:
+		imports[IMPORT_UTIL] = true
+		return "util.Uint160", nil
+	case smartcontract.Hash256Type:
+		imports[IMPORT_UTIL] = true
+		return "util.Uint256", nil
+	case smartcontract.PublicKeyType:
+		imports[IMPORT_KEYS] = true
+		return "*keys.PublicKey", nil
+	}
+	if !result {
+		switch t {
+		case smartcontract.ArrayType:
+			return "[]interface{}", nil
+		case smartcontract.MapType:
+			return "map[interface{}]interface{}", nil
+		case smartcontract.AnyType, smartcontract.InteropInterfaceType:
+			return "interface{}", nil
+		}
+	} else {
+		switch t {
+		case smartcontract.ArrayType:
+			imports[IMPORT_STACKITEM] = true
+			return "[]stackitem.Item", nil
+		case smartcontract.MapType:
+			imports[IMPORT_STACKITEM] = true
+			return "*stackitem.Map", nil
+		case smartcontract.AnyType, smartcontract.InteropInterfaceType:
+			imports[IMPORT_STACKITEM] = true
+			return "stackitem.Item", nil
+		}
+	}
+	return "", fmt.Errorf("unsupported type %s", t)
+}
+
+// exportedName turns a method name such as balanceOf or get_owner into BalanceOf or GetOwner
+func exportedName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	s := b.String()
+	if s == "" || !unicode.IsLetter([]rune(s)[0]) {
+		s = "M" + s
+	}
+	return s
+}
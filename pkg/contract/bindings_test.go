@@ -0,0 +1,63 @@
+package contract_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/configwizard/gaspump-api/pkg/contract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func testManifest() *manifest.Manifest {
+	m := manifest.NewManifest("Pump")
+	m.ABI.Methods = []manifest.Method{
+		{Name: "_deploy", Parameters: []manifest.Parameter{{Name: "data", Type: smartcontract.AnyType}, {Name: "update", Type: smartcontract.BoolType}}, ReturnType: smartcontract.VoidType},
+		{Name: "symbol", ReturnType: smartcontract.StringType, Safe: true},
+		{Name: "balanceOf", Parameters: []manifest.Parameter{{Name: "account", Type: smartcontract.Hash160Type}}, ReturnType: smartcontract.IntegerType, Safe: true},
+		{Name: "tokens", ReturnType: smartcontract.InteropInterfaceType, Safe: true},
+		{Name: "transfer", Parameters: []manifest.Parameter{
+			{Name: "from", Type: smartcontract.Hash160Type}, {Name: "to", Type: smartcontract.Hash160Type},
+			{Name: "amount", Type: smartcontract.IntegerType}, {Name: "data", Type: smartcontract.AnyType}}, ReturnType: smartcontract.BoolType},
+		{Name: "transfer", Parameters: []manifest.Parameter{
+			{Name: "to", Type: smartcontract.Hash160Type}, {Name: "type", Type: smartcontract.ByteArrayType}}, ReturnType: smartcontract.BoolType},
+		{Name: "set_owner", Parameters: []manifest.Parameter{{Name: "key", Type: smartcontract.PublicKeyType}}, ReturnType: smartcontract.VoidType},
+		{Name: "hash", ReturnType: smartcontract.Hash256Type, Safe: true},
+	}
+	return m
+}
+
+func TestGenerateBindings(t *testing.T) {
+	src, err := contract.GenerateBindings(testManifest(), util.Uint160{1, 2, 3}, "pump")
+	assert.Nil(t, err, "error not nil")
+
+	f, err := parser.ParseFile(token.NewFileSet(), "pump.go", src, 0)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, "pump", f.Name.Name)
+	funcs := map[string]*ast.FuncDecl{}
+	for _, d := range f.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok {
+			funcs[fn.Name.Name] = fn
+		}
+	}
+	for _, name := range []string{"New", "Symbol", "BalanceOf", "Tokens", "Transfer4", "Transfer2", "SetOwner", "HashMethod"} {
+		assert.Contains(t, funcs, name)
+	}
+	assert.NotContains(t, funcs, "Deploy")
+	// safe methods return their result, the others take the signing account
+	assert.Len(t, funcs["BalanceOf"].Type.Results.List, 2)
+	assert.Equal(t, "acc", funcs["SetOwner"].Type.Params.List[1].Names[0].Name)
+	assert.Equal(t, "arg1", funcs["Transfer2"].Type.Params.List[3].Names[0].Name)
+
+	// without a hash New takes one
+	src, err = contract.GenerateBindings(testManifest(), util.Uint160{}, "pump")
+	assert.Nil(t, err, "error not nil")
+	assert.Contains(t, string(src), "func New(rpc *wallet2.RPCClient, hash util.Uint160) *Contract")
+
+	_, err = contract.GenerateBindings(testManifest(), util.Uint160{}, "not a package")
+	assert.NotNil(t, err, "invalid package name accepted")
+}
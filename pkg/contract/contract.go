@@ -0,0 +1,64 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+
+	wallet2 "github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+)
+
+// Contract calls the methods of a deployed contract with typed arguments, see wallet.ToParameter for how
+// Go values are passed and wallet.FromStackItem for how results are decoded
+type Contract struct {
+	rpc  *wallet2.RPCClient
+	hash util.Uint160
+}
+
+func New(rpc *wallet2.RPCClient, hash util.Uint160) *Contract {
+	return &Contract{rpc: rpc, hash: hash}
+}
+
+func (c *Contract) Hash() util.Uint160 {
+	return c.hash
+}
+
+// TestInvoke runs method on the node without sending a transaction
+func (c *Contract) TestInvoke(ctx context.Context, method string, args ...interface{}) (*wallet2.InvokeResult, error) {
+	var res *wallet2.InvokeResult
+	err := c.rpc.Do(ctx, func(cli *client.Client) (err error) {
+		res, err = wallet2.NewTxBuilder(cli).Call(c.hash, method, args...).TestInvoke()
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't call %s: %w", method, err)
+	}
+	return res, nil
+}
+
+// Call test invokes a read only method and decodes what it returns into result, which may be nil to ignore it
+func (c *Contract) Call(ctx context.Context, method string, result interface{}, args ...interface{}) error {
+	res, err := c.TestInvoke(ctx, method, args...)
+	if err != nil {
+		return err
+	}
+	if err := res.Err(); err != nil {
+		return fmt.Errorf("%s failed: %w", method, err)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := res.Decode(0, result); err != nil {
+		return fmt.Errorf("can't decode result of %s: %w", method, err)
+	}
+	return nil
+}
+
+// Invoke calls method in a transaction signed by acc and sends it. Wait for it with a wallet.Tracker
+func (c *Contract) Invoke(ctx context.Context, acc *wallet.Account, method string, args ...interface{}) (*wallet2.PreparedTx, error) {
+	return c.rpc.Invoke(ctx, acc, func(b *wallet2.TxBuilder) {
+		b.Call(c.hash, method, args...)
+	})
+}
@@ -0,0 +1,190 @@
+package cost
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/configwizard/gaspump-api/pkg/balance"
+	container2 "github.com/configwizard/gaspump-api/pkg/container"
+	"github.com/nspcc-dev/neofs-sdk-go/client"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/netmap"
+	"github.com/nspcc-dev/neofs-sdk-go/policy"
+)
+
+const (
+	// NEOFS_PRECISION is the precision of the side chain balance contract that fees are charged in
+	NEOFS_PRECISION = 12
+	// DEFAULT_ALPHABET_SIZE is the number of inner ring alphabet nodes, each of which is paid the container fee
+	DEFAULT_ALPHABET_SIZE = 7
+	// gigabyte is the unit the basic income rate is charged per, as in the inner ring settlement code
+	gigabyte = 1 << 30
+
+	BASIC_INCOME_RATE_KEY = "BasicIncomeRate"
+	CONTAINER_FEE_KEY     = "ContainerFee"
+	EPOCH_DURATION_KEY    = "EpochDuration"
+)
+
+// NetworkParams are the network settings that determine what storage costs
+type NetworkParams struct {
+	// BasicIncomeRate is paid to each storage node per gigabyte stored per epoch
+	BasicIncomeRate uint64
+	// ContainerFee is paid to each alphabet node when a container is created
+	ContainerFee uint64
+	// EpochDuration is in blocks
+	EpochDuration uint64
+	MsPerBlock    int64
+	CurrentEpoch  uint64
+	AlphabetSize  uint64
+}
+
+// GetNetworkParams reads the fee settings from the network
+func GetNetworkParams(ctx context.Context, cli *client.Client) (*NetworkParams, error) {
+	res, err := cli.NetworkInfo(ctx, client.PrmNetworkInfo{})
+	if err != nil {
+		return nil, fmt.Errorf("can't get network info: %w", err)
+	}
+	return NetworkParamsFromInfo(res.Info())
+}
+
+// NetworkParamsFromInfo extracts the fee settings from network info. AlphabetSize is set to DEFAULT_ALPHABET_SIZE
+// as the network does not report it
+func NetworkParamsFromInfo(info *netmap.NetworkInfo) (*NetworkParams, error) {
+	p := &NetworkParams{
+		MsPerBlock:   info.MsPerBlock(),
+		CurrentEpoch: info.CurrentEpoch(),
+		AlphabetSize: DEFAULT_ALPHABET_SIZE,
+	}
+	found := map[string]bool{}
+	if cfg := info.NetworkConfig(); cfg != nil {
+		cfg.IterateParameters(func(parameter *netmap.NetworkParameter) bool {
+			var dst *uint64
+			switch string(parameter.Key()) {
+			case BASIC_INCOME_RATE_KEY:
+				dst = &p.BasicIncomeRate
+			case CONTAINER_FEE_KEY:
+				dst = &p.ContainerFee
+			case EPOCH_DURATION_KEY:
+				dst = &p.EpochDuration
+			default:
+				return false
+			}
+			data := make([]byte, 8)
+			copy(data, parameter.Value())
+			*dst = binary.LittleEndian.Uint64(data)
+			found[string(parameter.Key())] = true
+			return false
+		})
+	}
+	for _, key := range []string{BASIC_INCOME_RATE_KEY, CONTAINER_FEE_KEY, EPOCH_DURATION_KEY} {
+		if !found[key] {
+			return nil, fmt.Errorf("not found param: %s", key)
+		}
+	}
+	return p, nil
+}
+
+// EpochLength is roughly how long an epoch lasts
+func (p *NetworkParams) EpochLength() time.Duration {
+	return time.Duration(p.EpochDuration) * time.Duration(p.MsPerBlock) * time.Millisecond
+}
+
+// EpochsFor returns how many epochs cover d, rounding up
+func (p *NetworkParams) EpochsFor(d time.Duration) uint64 {
+	epoch := p.EpochLength()
+	if epoch <= 0 || d <= 0 {
+		return 0
+	}
+	return uint64((d + epoch - 1) / epoch)
+}
+
+// ReplicaCount is how many copies of each object a placement policy stores
+func ReplicaCount(p *netmap.PlacementPolicy) uint32 {
+	var n uint32
+	for _, r := range p.Replicas() {
+		n += r.Count()
+	}
+	return n
+}
+
+// Estimate is the expected price of storing a payload, in NeoFS balance units
+type Estimate struct {
+	Size     uint64 `json:"size"`
+	Replicas uint32 `json:"replicas"`
+	Epochs   uint64 `json:"epochs"`
+	// Duration is how long Epochs lasts at the current block time
+	Duration time.Duration    `json:"duration"`
+	PerEpoch *balance.Balance `json:"perEpoch"`
+	Storage  *balance.Balance `json:"storage"`
+	// ContainerFee is only set when the estimate includes creating a container
+	ContainerFee *balance.Balance `json:"containerFee"`
+	Total        *balance.Balance `json:"total"`
+}
+
+// EstimateStorage prices size bytes stored with replicas copies for epochs, the way the inner ring settles basic income.
+// Set newContainer to include the fee for creating the container
+func EstimateStorage(params *NetworkParams, replicas uint32, size, epochs uint64, newContainer bool) (*Estimate, error) {
+	if replicas == 0 {
+		return nil, errors.New("placement policy stores no replicas")
+	}
+	perEpoch := new(big.Int).SetUint64(size)
+	perEpoch.Mul(perEpoch, new(big.Int).SetUint64(uint64(replicas)))
+	perEpoch.Mul(perEpoch, new(big.Int).SetUint64(params.BasicIncomeRate))
+	perEpoch.Quo(perEpoch, big.NewInt(gigabyte))
+	storage := new(big.Int).Mul(perEpoch, new(big.Int).SetUint64(epochs))
+
+	fee := new(big.Int)
+	if newContainer {
+		fee.SetUint64(params.ContainerFee)
+		fee.Mul(fee, new(big.Int).SetUint64(params.AlphabetSize))
+	}
+	total := new(big.Int).Add(storage, fee)
+	if !total.IsInt64() {
+		return nil, errors.New("estimate overflows")
+	}
+
+	return &Estimate{
+		Size:         size,
+		Replicas:     replicas,
+		Epochs:       epochs,
+		Duration:     time.Duration(epochs) * params.EpochLength(),
+		PerEpoch:     &balance.Balance{Value: perEpoch.Int64(), Precision: NEOFS_PRECISION},
+		Storage:      &balance.Balance{Value: storage.Int64(), Precision: NEOFS_PRECISION},
+		ContainerFee: &balance.Balance{Value: fee.Int64(), Precision: NEOFS_PRECISION},
+		Total:        &balance.Balance{Value: total.Int64(), Precision: NEOFS_PRECISION},
+	}, nil
+}
+
+// EstimateForPolicy prices storage in a container that would be created with placementPolicy, e.g. "REP 2",
+// including the container fee
+func EstimateForPolicy(ctx context.Context, cli *client.Client, placementPolicy string, size uint64, lifetime time.Duration) (*Estimate, error) {
+	p, err := policy.Parse(placementPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse placement policy: %w", err)
+	}
+	params, err := GetNetworkParams(ctx, cli)
+	if err != nil {
+		return nil, err
+	}
+	return EstimateStorage(params, ReplicaCount(p), size, params.EpochsFor(lifetime), true)
+}
+
+// EstimateForContainer prices storing size bytes in an existing container for lifetime
+func EstimateForContainer(ctx context.Context, cli *client.Client, containerID cid.ID, size uint64, lifetime time.Duration) (*Estimate, error) {
+	cnr, err := container2.Get(ctx, cli, containerID)
+	if err != nil {
+		return nil, err
+	}
+	if cnr.PlacementPolicy() == nil {
+		return nil, errors.New("container has no placement policy")
+	}
+	params, err := GetNetworkParams(ctx, cli)
+	if err != nil {
+		return nil, err
+	}
+	return EstimateStorage(params, ReplicaCount(cnr.PlacementPolicy()), size, params.EpochsFor(lifetime), false)
+}
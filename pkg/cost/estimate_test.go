@@ -0,0 +1,80 @@
+package cost_test
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/configwizard/gaspump-api/pkg/cost"
+	"github.com/nspcc-dev/neofs-sdk-go/netmap"
+	"github.com/nspcc-dev/neofs-sdk-go/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+func parameter(key string, value uint64) *netmap.NetworkParameter {
+	p := netmap.NewNetworkParameter()
+	p.SetKey([]byte(key))
+	data := make([]byte, 8)
+	binary.LittleEndian.PutUint64(data, value)
+	// the contract stores integers in their shortest form
+	for len(data) > 1 && data[len(data)-1] == 0 {
+		data = data[:len(data)-1]
+	}
+	p.SetValue(data)
+	return p
+}
+
+func testInfo() *netmap.NetworkInfo {
+	cfg := netmap.NewNetworkConfig()
+	cfg.SetParameters(
+		parameter("MaxObjectSize", 64<<20),
+		parameter(cost.BASIC_INCOME_RATE_KEY, 100_000_000),
+		parameter(cost.CONTAINER_FEE_KEY, 1_000),
+		parameter(cost.EPOCH_DURATION_KEY, 240),
+	)
+	info := netmap.NewNetworkInfo()
+	info.SetNetworkConfig(cfg)
+	info.SetMsPerBlock(15_000)
+	info.SetCurrentEpoch(100)
+	return info
+}
+
+func TestNetworkParams(t *testing.T) {
+	params, err := cost.NetworkParamsFromInfo(testInfo())
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, uint64(100_000_000), params.BasicIncomeRate)
+	assert.Equal(t, uint64(1_000), params.ContainerFee)
+	assert.Equal(t, uint64(240), params.EpochDuration)
+	assert.Equal(t, time.Hour, params.EpochLength())
+	assert.Equal(t, uint64(24), params.EpochsFor(24*time.Hour))
+	assert.Equal(t, uint64(25), params.EpochsFor(24*time.Hour+time.Minute))
+
+	_, err = cost.NetworkParamsFromInfo(netmap.NewNetworkInfo())
+	assert.NotNil(t, err, "missing parameters accepted")
+}
+
+func TestEstimateStorage(t *testing.T) {
+	params, err := cost.NetworkParamsFromInfo(testInfo())
+	assert.Nil(t, err, "error not nil")
+	p, err := policy.Parse("REP 2 IN X CBF 1 SELECT 2 FROM * AS X")
+	assert.Nil(t, err, "error not nil")
+	replicas := cost.ReplicaCount(p)
+	assert.Equal(t, uint32(2), replicas)
+
+	// half a gigabyte twice over costs one gigabyte's rate per epoch
+	e, err := cost.EstimateStorage(params, replicas, 1<<29, 24, false)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, int64(100_000_000), e.PerEpoch.Value)
+	assert.Equal(t, int64(2_400_000_000), e.Storage.Value)
+	assert.Equal(t, int64(0), e.ContainerFee.Value)
+	assert.Equal(t, "0.0024", e.Total.String())
+	assert.Equal(t, 24*time.Hour, e.Duration)
+
+	e, err = cost.EstimateStorage(params, replicas, 1<<29, 24, true)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, int64(7_000), e.ContainerFee.Value)
+	assert.Equal(t, int64(2_400_007_000), e.Total.Value)
+
+	_, err = cost.EstimateStorage(params, 0, 1, 1, false)
+	assert.NotNil(t, err, "zero replicas accepted")
+}
@@ -0,0 +1,94 @@
+package eacl
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/eacl"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+)
+
+// the tables in this file are intended to be attached to bearer tokens.
+// If allowedPubKey is nil the allow records target RoleOthers, which is how
+// a bearer token holder is seen by the storage nodes.
+
+var (
+	// ReadOperations are the operations needed to read objects
+	ReadOperations = []eacl.Operation{eacl.OperationGet, eacl.OperationHead, eacl.OperationSearch, eacl.OperationRange, eacl.OperationRangeHash}
+	// WriteOperations are the operations that change objects
+	WriteOperations = []eacl.Operation{eacl.OperationPut, eacl.OperationDelete}
+)
+
+// ReadOnlyEACL allows the key to read every object in the container and denies writes to everyone else
+func ReadOnlyEACL(containerID cid.ID, allowedPubKey *keys.PublicKey) eacl.Table {
+	table := eacl.NewTable()
+	table.SetCID(&containerID)
+	AddRecords(table, eacl.ActionAllow, allowTarget(allowedPubKey), ReadOperations)
+	AddRecords(table, eacl.ActionDeny, othersTarget(), WriteOperations)
+	return *table
+}
+
+// UploadOnlyEACL allows the key to put objects into the container (a drop box) and denies everything else
+func UploadOnlyEACL(containerID cid.ID, allowedPubKey *keys.PublicKey) eacl.Table {
+	table := eacl.NewTable()
+	table.SetCID(&containerID)
+	AddRecords(table, eacl.ActionAllow, allowTarget(allowedPubKey), []eacl.Operation{eacl.OperationPut})
+	AddRecords(table, eacl.ActionDeny, othersTarget(), append(ReadOperations, eacl.OperationDelete))
+	return *table
+}
+
+// SingleObjectReadEACL allows the key to download a single object and denies access to every other object
+func SingleObjectReadEACL(containerID cid.ID, objectID oid.ID, allowedPubKey *keys.PublicKey) eacl.Table {
+	table := eacl.NewTable()
+	table.SetCID(&containerID)
+	target := allowTarget(allowedPubKey)
+	for _, v := range []eacl.Operation{eacl.OperationGet, eacl.OperationHead, eacl.OperationRange, eacl.OperationRangeHash} {
+		allowRecord := eacl.CreateRecord(eacl.ActionAllow, v)
+		allowRecord.AddObjectIDFilter(eacl.MatchStringEqual, &objectID)
+		allowRecord.SetTargets(target)
+		table.AddRecord(allowRecord)
+	}
+	AddRecords(table, eacl.ActionDeny, othersTarget(), append(ReadOperations, WriteOperations...))
+	return *table
+}
+
+// AttributeReadEACL allows the key to read objects carrying the attribute key=value and denies access to all other objects
+func AttributeReadEACL(containerID cid.ID, key, value string, allowedPubKey *keys.PublicKey) eacl.Table {
+	table := eacl.NewTable()
+	table.SetCID(&containerID)
+	target := allowTarget(allowedPubKey)
+	for _, v := range ReadOperations {
+		allowRecord := eacl.CreateRecord(eacl.ActionAllow, v)
+		if v != eacl.OperationSearch {
+			//search requests carry no object header, so they can't be matched on an attribute
+			allowRecord.AddObjectAttributeFilter(eacl.MatchStringEqual, key, value)
+		}
+		allowRecord.SetTargets(target)
+		table.AddRecord(allowRecord)
+	}
+	AddRecords(table, eacl.ActionDeny, othersTarget(), append(ReadOperations, WriteOperations...))
+	return *table
+}
+
+// AddRecords adds a record with the action for each of the operations, applied to the target
+func AddRecords(table *eacl.Table, action eacl.Action, target *eacl.Target, operations []eacl.Operation) {
+	for _, v := range operations {
+		record := eacl.CreateRecord(action, v)
+		record.SetTargets(target)
+		table.AddRecord(record)
+	}
+}
+
+func allowTarget(allowedPubKey *keys.PublicKey) *eacl.Target {
+	if allowedPubKey == nil {
+		return othersTarget()
+	}
+	target := eacl.NewTarget()
+	target.SetBinaryKeys([][]byte{allowedPubKey.Bytes()})
+	return target
+}
+
+func othersTarget() *eacl.Target {
+	target := eacl.NewTarget()
+	target.SetRole(eacl.RoleOthers)
+	return target
+}
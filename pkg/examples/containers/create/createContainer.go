@@ -3,18 +3,18 @@ package main
 import (
 	"context"
 	client2 "github.com/configwizard/gaspump-api/pkg/client"
+	"github.com/configwizard/gaspump-api/pkg/signer"
 	"github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neofs-sdk-go/acl"
 
 	"encoding/json"
 	"flag"
 	"fmt"
 	container2 "github.com/configwizard/gaspump-api/pkg/container"
-	"github.com/nspcc-dev/neofs-sdk-go/container"
 	"io/ioutil"
 	"log"
 	"os"
-	"time"
 )
 
 const usage = `Example
@@ -28,6 +28,7 @@ var (
 	walletAddr = flag.String("address", "", "wallets address [optional]")
 	createWallet = flag.Bool("create", false, "create a wallets")
 	password = flag.String("password", "", "wallet password")
+	name = flag.String("name", "", "container name [optional]")
 
 )
 
@@ -62,29 +63,21 @@ func main() {
 	if err != nil {
 		log.Fatal("can't create NeoFS client:", err)
 	}
-	var attributes []*container.Attribute
 	placementPolicy := `REP 2 IN X 
 	CBF 2
 	SELECT 2 FROM * AS X
 	`
-	customACL := acl.BasicACL(acl.EACLPublicBasicRule)
-
-	id, err := container2.Create(ctx, cli, key, placementPolicy, customACL, attributes)
+	id, err := container2.Create(ctx, cli, signer.NewKeySigner(key), container2.CreateContainerParams{
+		PlacementPolicy: placementPolicy,
+		BasicACL:        acl.EACLPublicBasicRule,
+		Name:            *name,
+		ClientKey:       (*keys.PublicKey)(&key.PublicKey),
+		// wait until the container has been persisted in side chain
+		Wait: true,
+	})
 	if err != nil {
 		log.Fatal(err)
 	}
-
-	// Poll container ID until it will be available in the network.
-	for i := 0; i <= 30; i++ {
-		if i == 30 {
-			log.Fatalf("Timeout, container %s was not persisted in side chain\n", id)
-		}
-		_, err = container2.Get(ctx, cli, *id)
-		if err == nil {
-			break
-		}
-		time.Sleep(time.Second)
-	}
 	//e.g 2qo7LZDDHJBN833dVkyDy5gwP65qBMV5uYiFMfVLjMMA
 	fmt.Printf("Container %s has been persisted in side chain\n", id)
 }
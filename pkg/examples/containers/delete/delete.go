@@ -7,9 +7,12 @@ import (
 	"fmt"
 	client2 "github.com/configwizard/gaspump-api/pkg/client"
 	container2 "github.com/configwizard/gaspump-api/pkg/container"
+	"github.com/configwizard/gaspump-api/pkg/signer"
 	"github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neofs-sdk-go/client"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
 	"github.com/nspcc-dev/neofs-sdk-go/owner"
+	"github.com/nspcc-dev/neofs-sdk-go/session"
 	"io/ioutil"
 	"log"
 	"os"
@@ -71,14 +74,13 @@ func main() {
 	}
 	cntID := cid.ID{}
 	cntID.Parse(*containerID)
-	sessionToken, err := client2.CreateSessionWithContainerDeleteContext(ctx, cli, ownerID, cntID, client2.GetHelperTokenExpiry(ctx, cli, 10), key)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	//sessionToken, err := client2.CreateSession(ctx, cli, client2.GetHelperTokenExpiry(ctx, cli, 10), key)
-
-	res, err := container2.Delete(ctx, cli, cntID, sessionToken)
+	sessions := client2.NewSessionManager(cli, signer.NewKeySigner(key), ownerID, 0)
+	// Do opens a session again and retries if the node says the token has expired
+	var res *client.ResContainerDelete
+	err = sessions.Do(ctx, client2.ContainerDelete, client2.ContainerScope(cntID), func(sessionToken *session.Token) (err error) {
+		res, err = container2.Delete(ctx, cli, cntID, sessionToken)
+		return err
+	})
 	if err != nil {
 		log.Fatal("could not delete containers", err)
 	}
@@ -6,6 +6,7 @@ import (
 	"flag"
 	"fmt"
 	client2 "github.com/configwizard/gaspump-api/pkg/client"
+	"github.com/configwizard/gaspump-api/pkg/signer"
 	container2 "github.com/configwizard/gaspump-api/pkg/container"
 	"github.com/configwizard/gaspump-api/pkg/wallet"
 	"io/ioutil"
@@ -60,7 +61,7 @@ func main() {
 		log.Fatal("can't create NeoFS client:", err)
 	}
 
-	list, err := container2.List(ctx, cli, key)
+	list, err := container2.List(ctx, cli, signer.NewKeySigner(key))
 
 	if err != nil {
 		log.Fatal("could not list containers", err)
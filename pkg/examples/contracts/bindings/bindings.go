@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/configwizard/gaspump-api/pkg/contract"
+	"github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+const usage = `Example
+
+$ ./bindings -manifest nft.manifest.json -hash 0x2d1e4bb0b39eb8a7c13b6c41d8d6ff5a5d0d8ce5 -package nft -out nft/nft.go
+`
+
+var (
+	manifestPath = flag.String("manifest", "", "contract manifest JSON")
+	hashStr      = flag.String("hash", "", "script hash of the deployed contract, leave empty to pass it to New")
+	pkg          = flag.String("package", "", "package name of the bindings")
+	out          = flag.String("out", "", "file to write, stdout if empty")
+)
+
+func main() {
+	flag.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	m, err := contract.LoadManifest(*manifestPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var hash util.Uint160
+	if *hashStr != "" {
+		if hash, _, err = wallet.ConvertScriptHashToAddressString(*hashStr); err != nil {
+			log.Fatal("invalid hash: ", err)
+		}
+	}
+	src, err := contract.GenerateBindings(m, hash, *pkg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		fmt.Print(string(src))
+		return
+	}
+	if err := ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	netmap2 "github.com/configwizard/gaspump-api/pkg/netmap"
+	"github.com/configwizard/gaspump-api/pkg/nns"
+	"github.com/configwizard/gaspump-api/pkg/wallet"
+	rpcclient "github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neofs-sdk-go/client"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+)
+
+const usage = `Example
+
+$ ./netmap -wallets ./sample_wallets/wallet.json -password password -sidechain http://localhost:30333 -container 7L9hkS... -object 9nQhoD...
+prints the storage node the client is connected to, the current netmap and the nodes the container and object are stored on
+`
+
+var (
+	walletPath  = flag.String("wallets", "", "path to JSON wallets file")
+	walletAddr  = flag.String("address", "", "wallets address [optional]")
+	password    = flag.String("password", "", "wallet password")
+	neofsAddr   = flag.String("neofs", "grpcs://st01.testnet.fs.neo.org:8082", "storage node to connect to")
+	sidechain   = flag.String("sidechain", "", "RPC endpoint of a NeoFS chain node")
+	containerID = flag.String("container", "", "container to show the nodes of [optional]")
+	objectID    = flag.String("object", "", "object of the container to show the nodes of [optional]")
+)
+
+func main() {
+	flag.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	ctx := context.Background()
+
+	key, err := wallet.GetCredentialsFromPath(*walletPath, *walletAddr, *password)
+	if err != nil {
+		log.Fatal("can't read credentials:", err)
+	}
+	cli, err := client.New(
+		client.WithDefaultPrivateKey(key),
+		client.WithURIAddress(*neofsAddr, nil),
+		client.WithNeoFSErrorParsing(),
+	)
+	if err != nil {
+		log.Fatal("can't create NeoFS client:", err)
+	}
+	local, err := netmap2.LocalNode(ctx, cli)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("connected to", local)
+
+	// the netmap is kept by the netmap contract on the NeoFS chain
+	rpc, err := wallet.NewRPCClient(ctx, rpcclient.Options{}, wallet.RPC_NETWORK(*sidechain))
+	if err != nil {
+		log.Fatal("can't connect to NeoFS chain:", err)
+	}
+	r, err := nns.NewResolver(ctx, rpc)
+	if err != nil {
+		log.Fatal(err)
+	}
+	e, err := netmap2.NewExplorer(ctx, rpc, r)
+	if err != nil {
+		log.Fatal(err)
+	}
+	epoch, err := e.Epoch(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	nodes, err := e.Snapshot(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("netmap of epoch %d\n", epoch)
+	for _, n := range netmap2.Nodes(nodes) {
+		fmt.Println(n)
+	}
+
+	if *containerID == "" {
+		return
+	}
+	cntID := cid.New()
+	if err := cntID.Parse(*containerID); err != nil {
+		log.Fatal("invalid container ID:", err)
+	}
+	var placement *netmap2.Placement
+	if *objectID == "" {
+		placement, err = e.ContainerPlacement(ctx, cli, *cntID)
+	} else {
+		objID := oid.NewID()
+		if err := objID.Parse(*objectID); err != nil {
+			log.Fatal("invalid object ID:", err)
+		}
+		placement, err = e.ObjectPlacement(ctx, cli, *cntID, *objID)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(placement)
+}
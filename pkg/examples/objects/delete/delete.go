@@ -7,6 +7,7 @@ import (
 	"flag"
 	"fmt"
 	client2 "github.com/configwizard/gaspump-api/pkg/client"
+	"github.com/configwizard/gaspump-api/pkg/signer"
 	eacl2 "github.com/configwizard/gaspump-api/pkg/eacl"
 	"github.com/configwizard/gaspump-api/pkg/object"
 	"github.com/configwizard/gaspump-api/pkg/wallet"
@@ -80,12 +81,15 @@ func main() {
 
 	cntId := cid.ID{}
 	cntId.Parse(*containerID)
+	objID := oid.ID{}
+	objID.Parse(*objectID)
 
 	ownerID := owner.NewID()
 	ownerID, err = wallet.OwnerIDFromPrivateKey(key)
 	if err != nil {
 		log.Fatal("cant retrieve ownerID:", err)
 	}
+	sessions := client2.NewSessionManager(cli, signer.NewKeySigner(key), ownerID, 0)
 	//pointers so we can have nil tokens
 	var sessionToken = &session.Token{}
 	var bearerToken = &token.BearerToken{}
@@ -104,7 +108,7 @@ func main() {
 			log.Fatal("cant create eacl table:", err)
 		}
 		//(tokenReceiver *owner.ID, expire uint64, eaclTable *eacl.Table, containerOwnerKey *ecdsa.PrivateKey) (*token.BearerToken, error){
-		bearerToken, err = client2.NewBearerToken(ownerID, getHelperTokenExpiry(ctx, cli), eaclTable, true, key)
+		bearerToken, err = client2.NewBearerToken(ctx, ownerID, getHelperTokenExpiry(ctx, cli), eaclTable, signer.NewKeySigner(key))
 
 		marshalBearerToken, err := client2.MarshalBearerToken(*bearerToken)
 		if err != nil {
@@ -117,16 +121,13 @@ func main() {
 	} else {
 		log.Println("using session token...")
 		bearerToken = nil
-		sessionToken, err = client2.CreateSessionWithObjectDeleteContext(ctx, cli, ownerID, cntId, client2.GetHelperTokenExpiry(ctx, cli, 10), key)
+		sessionToken, err = sessions.Token(ctx, client2.ObjectDelete, client2.ObjectScope(cntId, objID))
 		if err != nil {
 			log.Fatal(err)
 		}
 	}
 	//Hw5z3F78HrgmCgUqw8KNkcgtaEcmv66Zxr193Nj1ZSnd
 
-
-	objID := oid.ID{}
-	objID.Parse(*objectID)
 	//fmt.Printf("bearer %+v \r\n session %+v\r\n", bearerToken, sessionToken)
 	res, err := object.DeleteObject(ctx, cli, objID, cntId, bearerToken, sessionToken)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"flag"
 	"fmt"
 	client2 "github.com/configwizard/gaspump-api/pkg/client"
+	"github.com/configwizard/gaspump-api/pkg/signer"
 	eacl2 "github.com/configwizard/gaspump-api/pkg/eacl"
 	"github.com/configwizard/gaspump-api/pkg/object"
 	"github.com/configwizard/gaspump-api/pkg/wallet"
@@ -84,6 +85,7 @@ func main() {
 	if err != nil {
 		log.Fatal("cant retrieve ownerID:", err)
 	}
+	sessions := client2.NewSessionManager(cli, signer.NewKeySigner(key), ownerID, 0)
 	//pointers so we can have nil tokens
 	var sessionToken = &session.Token{}
 	var bearerToken = &token.BearerToken{}
@@ -102,7 +104,7 @@ func main() {
 			log.Fatal("cant create eacl table:", err)
 		}
 		//(tokenReceiver *owner.ID, expire uint64, eaclTable *eacl.Table, containerOwnerKey *ecdsa.PrivateKey) (*token.BearerToken, error){
-		bearerToken, err = client2.NewBearerToken(ownerID, getHelperTokenExpiry(ctx, cli), eaclTable, true, key)
+		bearerToken, err = client2.NewBearerToken(ctx, ownerID, getHelperTokenExpiry(ctx, cli), eaclTable, signer.NewKeySigner(key))
 
 		marshalBearerToken, err := client2.MarshalBearerToken(*bearerToken)
 		if err != nil {
@@ -115,7 +117,7 @@ func main() {
 	} else {
 		log.Println("using session token...")
 		bearerToken = nil
-		sessionToken, err = client2.CreateSessionForContainerList(ctx, cli, getHelperTokenExpiry(ctx, cli), key)
+		sessionToken, err = sessions.Token(ctx, client2.ObjectSearch, client2.ContainerScope(cntId))
 		if err != nil {
 			log.Fatal(err)
 		}
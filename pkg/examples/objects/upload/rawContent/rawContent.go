@@ -10,6 +10,7 @@ import (
 	"fmt"
 	//"github.com/cheggaaa/pb"
 	client2 "github.com/configwizard/gaspump-api/pkg/client"
+	"github.com/configwizard/gaspump-api/pkg/signer"
 	eacl2 "github.com/configwizard/gaspump-api/pkg/eacl"
 	"github.com/configwizard/gaspump-api/pkg/object"
 	"github.com/configwizard/gaspump-api/pkg/wallet"
@@ -91,6 +92,7 @@ func main() {
 	if err != nil {
 		log.Fatal("cant retrieve ownerID:", err)
 	}
+	sessions := client2.NewSessionManager(cli, signer.NewKeySigner(key), ownerID, 0)
 	//pointers so we can have nil tokens
 	var sessionToken = &session.Token{}
 	var bearerToken = &token.BearerToken{}
@@ -106,7 +108,7 @@ func main() {
 
 		table := eacl2.PutAllowDenyOthersEACL(cntId, (*keys.PublicKey)(&key.PublicKey))
 		//bearerToken, err = client2.ExampleBearerToken(30, cntId, ownerID, info.CurrentEpoch(), specifiedTargetRole, eaclTable, key)
-		bearerToken, err = client2.NewBearerToken(ownerID, getHelperTokenExpiry(ctx, cli), table, true, key)
+		bearerToken, err = client2.NewBearerToken(ctx, ownerID, getHelperTokenExpiry(ctx, cli), table, signer.NewKeySigner(key))
 
 		marshalBearerToken, err := client2.MarshalBearerToken(*bearerToken)
 		if err != nil {
@@ -119,7 +121,7 @@ func main() {
 	} else {
 		log.Println("using session token...")
 		bearerToken = nil
-		sessionToken, err = client2.CreateSession(ctx, cli, client2.GetHelperTokenExpiry(ctx, cli, 10), key)
+		sessionToken, err = sessions.Token(ctx, client2.ObjectPut, client2.ContainerScope(cntId))
 		if err != nil {
 			log.Fatal(err)
 		}
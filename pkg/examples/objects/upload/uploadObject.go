@@ -10,6 +10,7 @@ import (
 	"fmt"
 	//"github.com/cheggaaa/pb"
 	client2 "github.com/configwizard/gaspump-api/pkg/client"
+	"github.com/configwizard/gaspump-api/pkg/signer"
 	eacl2 "github.com/configwizard/gaspump-api/pkg/eacl"
 	"github.com/configwizard/gaspump-api/pkg/object"
 	"github.com/configwizard/gaspump-api/pkg/wallet"
@@ -97,6 +98,7 @@ func main() {
 	if err != nil {
 		log.Fatal("cant retrieve ownerID:", err)
 	}
+	sessions := client2.NewSessionManager(cli, signer.NewKeySigner(key), ownerID, 0)
 	//pointers so we can have nil tokens
 	var sessionToken = &session.Token{}
 	var bearerToken = &token.BearerToken{}
@@ -111,7 +113,7 @@ func main() {
 		eacl.SetTargetECDSAKeys(specifiedTargetRole, &key.PublicKey)
 
 		table := eacl2.PutAllowDenyOthersEACL(cntId, (*keys.PublicKey)(&key.PublicKey))
-		bearerToken, err = client2.NewBearerToken(ownerID, getHelperTokenExpiry(ctx, cli), table, true, key)
+		bearerToken, err = client2.NewBearerToken(ctx, ownerID, getHelperTokenExpiry(ctx, cli), table, signer.NewKeySigner(key))
 
 		marshalBearerToken, err := client2.MarshalBearerToken(*bearerToken)
 		if err != nil {
@@ -124,7 +126,7 @@ func main() {
 	} else {
 		log.Println("using session token...")
 		bearerToken = nil
-		sessionToken, err = client2.CreateSessionWithObjectPutContext(ctx, cli, ownerID, &cntId, client2.GetHelperTokenExpiry(ctx, cli, 10), key)
+		sessionToken, err = sessions.Token(ctx, client2.ObjectPut, client2.ContainerScope(cntId))
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -137,7 +139,7 @@ func main() {
 	}
 	filter := object2.SearchFilters{}
 	filter.AddRootFilter()
-	sessionToken, err = client2.CreateSessionForContainerList(ctx, cli, getHelperTokenExpiry(ctx, cli), key)
+	sessionToken, err = sessions.Token(ctx, client2.ObjectSearch, client2.ContainerScope(cntId))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	client2 "github.com/configwizard/gaspump-api/pkg/client"
 	eacl2 "github.com/configwizard/gaspump-api/pkg/eacl"
+	"github.com/configwizard/gaspump-api/pkg/keystore"
 	"github.com/configwizard/gaspump-api/pkg/examples/tokens/simple-share-server/api/objects"
 	"github.com/configwizard/gaspump-api/pkg/examples/tokens/simple-share-server/api/tokens"
 	"github.com/configwizard/gaspump-api/pkg/examples/tokens/simple-share-server/api/utils"
@@ -49,6 +50,8 @@ var (
 	//createWallet = flag.Bool("create", false, "create a wallets")
 	//useBearerToken = flag.Bool("bearer", false, "use a bearer token")
 	password = flag.String("password", "", "wallet password")
+	keystoreDir = flag.String("keystore", "", "keystore directory, used instead of the PRIVATE_KEY env var if set")
+	keyName = flag.String("key", "", "name of the key in the keystore")
 )
 
 func GetCredentialsFromPath(path, address, password string) (*ecdsa.PrivateKey, error) {
@@ -184,9 +187,19 @@ func main() {
 
 	//os.Setenv("PRIVATE_KEY", "")
 	// First obtain client credentials: private key of request owner
-	apiPrivateKey, err := keys.NewPrivateKeyFromHex(os.Getenv("PRIVATE_KEY"))
-	if err != nil {
-		log.Fatal("can't read credentials:", err)
+	var apiPrivateKey *keys.PrivateKey
+	if *keystoreDir != "" {
+		key, err := keystore.GetCredentials(*keystoreDir, *keyName, []byte(*password))
+		if err != nil {
+			log.Fatal("can't read credentials:", err)
+		}
+		apiPrivateKey = &keys.PrivateKey{PrivateKey: *key}
+	} else {
+		key, err := keys.NewPrivateKeyFromHex(os.Getenv("PRIVATE_KEY"))
+		if err != nil {
+			log.Fatal("can't read credentials:", err)
+		}
+		apiPrivateKey = key
 	}
 
 
@@ -30,7 +30,7 @@ func UnsignedBearerToken(cli *client.Client) http.HandlerFunc {
 		cntID.Parse(chi.URLParam(r, "containerId"))
 		kOwner := owner.NewIDFromPublicKey((*ecdsa.PublicKey)(k))
 		table := eacl2.PutAllowDenyOthersEACL(cntID, k)
-		bearer, err := client2.NewBearerToken(kOwner, utils.GetHelperTokenExpiry(ctx, cli), table, false, nil)
+		bearer, err := client2.NewBearerToken(ctx, kOwner, utils.GetHelperTokenExpiry(ctx, cli), table, nil)
 		if err != nil {
 			http.Error(w, err.Error(), code)
 			return
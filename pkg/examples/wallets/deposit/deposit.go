@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/configwizard/gaspump-api/pkg/balance"
+	client2 "github.com/configwizard/gaspump-api/pkg/client"
+	"github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+)
+
+const usage = `Example
+
+$ ./deposit -wallets ./sample_wallets/wallet.json -amount 1.5
+password is password
+`
+
+var (
+	walletPath = flag.String("wallets", "", "path to JSON wallets file")
+	walletAddr = flag.String("address", "", "wallets address [optional]")
+	password   = flag.String("password", "", "wallet password")
+	amount     = flag.String("amount", "1", "GAS to deposit, e.g. 1.5, or whole GAS to withdraw")
+	withdraw   = flag.Bool("withdraw", false, "withdraw from NeoFS instead of depositing")
+)
+
+func main() {
+	flag.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	// deposits take a few blocks on each chain to show up
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	acc, err := wallet.UnlockWallet(*walletPath, *walletAddr, *password)
+	if err != nil {
+		log.Fatal("can't unlock wallet:", err)
+	}
+	rpc, err := wallet.NewRPCClient(ctx, client.Options{}, wallet.RPC_TESTNET)
+	if err != nil {
+		log.Fatal(err)
+	}
+	cli, err := client2.NewClient(&acc.PrivateKey().PrivateKey, client2.TESTNET)
+	if err != nil {
+		log.Fatal("can't create NeoFS client:", err)
+	}
+	contract, err := balance.NeoFSContract(wallet.RPC_TESTNET)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	gasAmount, err := wallet.ParseAmount(*amount, balance.GAS_DECIMALS)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var res *balance.Result
+	if *withdraw {
+		res, err = balance.Withdraw(ctx, cli, rpc, acc, contract, gasAmount)
+	} else {
+		res, err = balance.Deposit(ctx, cli, rpc, acc, contract, gasAmount, nil)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("transaction %s used %d GAS fractions, NeoFS balance %s -> %s GAS\r\n", res.TxHash.StringLE(), res.GasUsed, res.Before, res.After)
+}
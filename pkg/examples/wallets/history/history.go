@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/configwizard/gaspump-api/pkg/balance"
+	"github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+)
+
+const usage = `Example
+
+$ ./history -address NadZ8YfvkddivcFFkztZgfwxZyKf1acpRF -page 0
+`
+
+var (
+	walletAddr = flag.String("address", "", "wallet address")
+	page       = flag.Int("page", 0, "page of history to show, newest first")
+)
+
+func main() {
+	flag.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	ctx := context.Background()
+	rpc, err := wallet.NewRPCClient(ctx, client.Options{}, wallet.RPC_TESTNET)
+	if err != nil {
+		log.Fatal(err)
+	}
+	contract, err := balance.NeoFSContract(wallet.RPC_TESTNET)
+	if err != nil {
+		log.Fatal(err)
+	}
+	activity, err := balance.GetActivity(ctx, rpc, contract, *walletAddr, wallet.HistoryQuery{Page: *page})
+	if err != nil {
+		log.Fatal("can't get activity: ", err)
+	}
+	for _, a := range activity.Entries {
+		fmt.Printf("%s %-10s %s %s\r\n", a.Time.Format("2006-01-02 15:04"), a.Kind, a.Amount.Format(a.Symbol), a.Counterparty)
+	}
+	for kind, totals := range activity.Totals {
+		for symbol, amount := range totals {
+			fmt.Printf("total %s: %s\r\n", kind, amount.Format(symbol))
+		}
+	}
+	if activity.More {
+		fmt.Printf("more with -page %d\r\n", *page+1)
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"flag"
 	"fmt"
 	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
-	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"math/big"
 	"time"
 
@@ -55,11 +54,11 @@ func main() {
 		os.Exit(0)
 	}
 
-	cli, err := client.New(ctx, string(wallet.RPC_TESTNET), client.Options{})
+	rpc, err := wallet.NewRPCClient(ctx, client.Options{}, wallet.RPC_TESTNET)
 	if err != nil {
 		log.Fatal(err)
 	}
-	err = cli.Init()
+	cli, err := rpc.Client(ctx)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -70,20 +69,44 @@ func main() {
 	}
 
 	account, err := wallet.StringToUint160(acc.Address)
-	param := smartcontract.Parameter{
-		Type:  smartcontract.Hash160Type,
-		Value: account,
+	if err != nil {
+		log.Fatal(err)
+	}
+	contract, _, err := wallet.ConvertScriptHashToAddressString("0x0a81b80376a65003781f140d1b87b6531f706215")
+	if err != nil {
+		log.Fatal(err)
 	}
-	params := []smartcontract.Parameter{param}
-	operation := "balanceOf" //symbol
-	network := wallet.RPC_TESTNET
-	transactionID, transaction, err := wallet.CreateTransactionFromFunctionCall("0x0a81b80376a65003781f140d1b87b6531f706215", operation, network, acc, params)
+	builder := wallet.NewTxBuilder(cli).Call(contract, "balanceOf", account).AddAccount(acc)
+
+	// dry run, nothing is sent
+	invocation, err := builder.TestInvoke()
+	if err != nil {
+		log.Fatal("test invoke failed ", err)
+	}
+	balance, err := invocation.Int(0)
+	if err != nil {
+		log.Fatal("can't decode result ", err)
+	}
+	log.Printf("balanceOf returns %s, consuming %d GAS units\r\n", balance, invocation.GasConsumed)
+
+	prepared, err := builder.Build()
+	if err != nil {
+		log.Fatal("can't build transaction ", err)
+	}
+	log.Printf("system fee %d, network fee %d, total %d\r\n", prepared.SystemFee(), prepared.NetworkFee(), prepared.TotalFee())
+	if err := prepared.Sign(acc); err != nil {
+		log.Fatal("can't sign transaction ", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+	applicationLog, err := prepared.Submit(ctx, cli)
 	if err != nil {
 		log.Fatal("transaction failed ", err)
 	}
-	log.Printf("success: transaction %+v\r\nSleeping...", transaction)
-	time.Sleep(20 * time.Second) //wait for the network to process transaction
-	applicationLog, err := wallet.GetLogForTransaction(wallet.RPC_TESTNET, transactionID)
+	if err := wallet.ExecutionError(applicationLog); err != nil {
+		log.Fatal(err)
+	}
 	for _, v := range applicationLog.Executions {
 		log.Printf("v %+v\r\n", v)
 		for i, k := range v.Stack {
@@ -100,7 +123,5 @@ func main() {
 
 		}
 	}
-	fmt.Printf("err %s\r\nappl %+v\r\n, tID %s\r\n", err, applicationLog, transactionID.StringLE())
+	fmt.Printf("tID %s\r\n", prepared.Tx.Hash().StringLE())
 }
-
-
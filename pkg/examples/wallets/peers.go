@@ -1,13 +1,21 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
 	"os"
 )
 
 func main() {
-	peers, err := wallet.GetPeers(wallet.RPC_TESTNET)
+	ctx := context.Background()
+	rpc, err := wallet.NewRPCClient(ctx, client.Options{}, wallet.RPC_TESTNET)
+	if err != nil {
+		fmt.Println("error connecting to RPC node", err)
+		os.Exit(2)
+	}
+	peers, err := wallet.GetPeers(ctx, rpc)
 	if err != nil {
 		fmt.Println("error retreiving peers", err)
 		os.Exit(2)
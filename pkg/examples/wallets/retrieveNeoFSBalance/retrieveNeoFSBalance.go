@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"github.com/configwizard/gaspump-api/pkg/balance"
 	"github.com/configwizard/gaspump-api/pkg/wallet"
 	"io/ioutil"
 	"log"
@@ -70,15 +71,14 @@ func main() {
 	if err != nil {
 		log.Fatal("can't get owner from private key:", err)
 	}
-	get := client.PrmBalanceGet{}
-	get.SetAccount(*owner)
-	result, err := cli.BalanceGet(ctx, get)
+	b, err := balance.GetBalance(ctx, cli, owner)
 	if err != nil {
 		log.Fatal("can't get NeoFS Balance:", err)
 	}
 
-	fmt.Println("value:", result.Amount().Value())
-	fmt.Println("precision:", result.Amount().Precision())
+	fmt.Println("value:", b.Value)
+	fmt.Println("balance:", b.String(), "GAS")
+	fmt.Println("precision:", b.Precision)
 }
 
 
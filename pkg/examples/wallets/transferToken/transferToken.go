@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
 	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"time"
 
 	//"github.com/configwizard/gaspump-api/pkg/examples/utils"
 	"github.com/configwizard/gaspump-api/pkg/wallet"
@@ -50,11 +52,7 @@ func main() {
 		os.Exit(0)
 	}
 
-	cli, err := client.New(ctx, string(wallet.RPC_TESTNET), client.Options{})
-	if err != nil {
-		log.Fatal(err)
-	}
-	err = cli.Init()
+	rpc, err := wallet.NewRPCClient(ctx, client.Options{}, wallet.RPC_TESTNET)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -63,17 +61,42 @@ func main() {
 	if err != nil {
 		log.Fatal("can't unlock wallet:", err)
 	}
-	gasToken, err := cli.GetNativeContractHash(nativenames.Gas)
+	gasToken, err := rpc.NativeHash(nativenames.Gas)
 	if err != nil {
 		log.Fatal(err)
 	}
-	//send 1 GAS (precision 8) to NeoFS wallet
+	//send 1 GAS to NeoFS wallet
 	//neoFSWallet := "NadZ8YfvkddivcFFkztZgfwxZyKf1acpRF"
-	token, err := wallet.TransferToken(w, 1_00_000_000, *recipient, gasToken, wallet.RPC_TESTNET)
+	info, err := rpc.TokenInfo(ctx, gasToken)
+	if err != nil {
+		log.Fatal(err)
+	}
+	amount, err := wallet.ParseTokenAmount("1 GAS", info)
+	if err != nil {
+		log.Fatal(err)
+	}
+	token, err := wallet.TransferToken(ctx, rpc, w, amount, *recipient, gasToken)
 	if err != nil {
 		log.Fatal("can't transfer token:", err)
 	}
-	log.Println("success: transaction ID ", token)
+	log.Println("sent: transaction ID ", token)
+
+	// wait for the transfer to be included in a block
+	txHash, err := util.Uint256DecodeStringLE(token)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+	tracker := wallet.NewTracker(rpc)
+	go tracker.Run(ctx)
+	res := <-tracker.WatchChan(txHash, 0)
+	if !res.Succeeded() {
+		log.Fatalf("transfer failed: %v %s", res.Err, res.FaultException)
+	}
+	for _, t := range res.Transfers {
+		log.Printf("transferred %s of %s to %s\r\n", t.Amount, t.Token.StringLE(), wallet.Uint160ToString(*t.To))
+	}
 }
 
 
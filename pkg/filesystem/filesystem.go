@@ -2,10 +2,11 @@ package filesystem
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"fmt"
 	"github.com/configwizard/gaspump-api/pkg/container"
+	"github.com/configwizard/gaspump-api/pkg/nns"
 	"github.com/configwizard/gaspump-api/pkg/object"
+	"github.com/configwizard/gaspump-api/pkg/signer"
 	"github.com/nspcc-dev/neofs-sdk-go/acl"
 	"github.com/nspcc-dev/neofs-sdk-go/client"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
@@ -15,10 +16,13 @@ import (
 	"github.com/nspcc-dev/neofs-sdk-go/session"
 	"github.com/nspcc-dev/neofs-sdk-go/token"
 	"path/filepath"
+	v2container "github.com/nspcc-dev/neofs-api-go/v2/container"
 )
 
 type Element struct {
 	ID string `json:"id"`
+	// Name is the NNS domain of a container, set by ResolveNames
+	Name string `json:"name,omitempty"`
 	Type string `josn:"type"`
 	Size uint64 `json:"size"`
 	BasicAcl acl.BasicACL
@@ -98,15 +102,38 @@ func GenerateObjectStruct(ctx context.Context, cli *client.Client, objs []oid.ID
 	return size, newObjs
 }
 
-//GenerateFileSystem returns an array of every object in every container the wallet key owns
-func GenerateFileSystem(ctx context.Context, cli *client.Client, key *ecdsa.PrivateKey, bearerToken *token.BearerToken, sessionToken *session.Token) ([]Element, error){
+//GenerateFileSystem returns an array of every object in every container the signer owns.
+//If r is not nil the containers are named by their NNS domains, see ResolveNames
+func GenerateFileSystem(ctx context.Context, cli *client.Client, s signer.Signer, r *nns.Resolver, bearerToken *token.BearerToken, sessionToken *session.Token) ([]Element, error){
 	var fileSystem []Element
-	containerIds, err := container.List(ctx, cli, key)
+	containerIds, err := container.List(ctx, cli, s)
 	if err != nil {
 		return []Element{}, err
 	}
 	for _, id := range containerIds {
 		fileSystem = append(fileSystem, GenerateFileSystemFromContainer(ctx, cli, *id, bearerToken, sessionToken))
 	}
+	if r != nil {
+		ResolveNames(ctx, r, fileSystem)
+	}
 	return fileSystem, nil
 }
+
+// ResolveNames sets the Name of container elements that were created with an NNS name still pointing at them.
+// Elements keep an empty Name if the lookup fails, so a listing still renders with the resolver unavailable
+func ResolveNames(ctx context.Context, r *nns.Resolver, elements []Element) {
+	for i := range elements {
+		e := &elements[i]
+		name, ok := e.Attributes[v2container.SysAttributeName]
+		if e.Type != "container" || !ok {
+			continue
+		}
+		id := cid.New()
+		if err := id.Parse(e.ID); err != nil {
+			continue
+		}
+		if domain, err := r.ReverseContainer(ctx, id, name, e.Attributes[v2container.SysAttributeZone]); err == nil {
+			e.Name = domain
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package filesystem_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/configwizard/gaspump-api/internal/rpctest"
+	"github.com/configwizard/gaspump-api/pkg/filesystem"
+	"github.com/configwizard/gaspump-api/pkg/nns"
+	"github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	v2container "github.com/nspcc-dev/neofs-api-go/v2/container"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/stretchr/testify/assert"
+)
+
+var nnsHash = util.Uint160{0x4e, 0x4e, 0x53}
+
+func containerID(seed string) *cid.ID {
+	id := cid.New()
+	id.SetSHA256(sha256.Sum256([]byte(seed)))
+	return id
+}
+
+func TestResolveNames(t *testing.T) {
+	ctx := context.Background()
+	photos, team, moved := containerID("photos"), containerID("team"), containerID("moved")
+	records := map[string]string{
+		"photos.container": photos.String(),
+		"docs.team":        team.String(),
+		"moved.container":  photos.String(),
+	}
+	node := rpctest.NewNode(t, netmode.TestNet)
+	node.Handle("invokescript", rpctest.InvokeScript(t, func(contract util.Uint160, method string, args []stackitem.Item) stackitem.Item {
+		assert.Equal(t, nnsHash, contract)
+		name, err := args[0].TryBytes()
+		assert.Nil(t, err, "error not nil")
+		if record, ok := records[string(name)]; ok {
+			return stackitem.NewArray([]stackitem.Item{stackitem.Make(record)})
+		}
+		return stackitem.Null{}
+	}))
+	rpc, err := wallet.NewRPCClient(ctx, client.Options{}, wallet.RPC_NETWORK(node.URL))
+	assert.Nil(t, err, "error not nil")
+	r := nns.NewResolverAt(rpc, nnsHash)
+
+	elements := []filesystem.Element{
+		{Type: "container", ID: photos.String(), Attributes: map[string]string{v2container.SysAttributeName: "photos"}},
+		{Type: "container", ID: team.String(), Attributes: map[string]string{
+			v2container.SysAttributeName: "docs", v2container.SysAttributeZone: "team"}},
+		// the name has since been given to another container
+		{Type: "container", ID: moved.String(), Attributes: map[string]string{v2container.SysAttributeName: "moved"}},
+		{Type: "container", ID: containerID("unnamed").String(), Attributes: map[string]string{}},
+		{Type: "object", ID: "object", Attributes: map[string]string{v2container.SysAttributeName: "photos"}},
+	}
+	filesystem.ResolveNames(ctx, r, elements)
+	names := make([]string, len(elements))
+	for i := range elements {
+		names[i] = elements[i].Name
+	}
+	assert.Equal(t, []string{"photos.container", "docs.team", "", "", ""}, names)
+}
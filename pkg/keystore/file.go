@@ -0,0 +1,114 @@
+package keystore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// KEY_FILE_EXTENSION is appended to the name of each key in a FileBackend
+const KEY_FILE_EXTENSION = ".key"
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// Backend stores sealed keys by name. It never sees plaintext key material
+type Backend interface {
+	Load(name string) ([]byte, error)
+	Save(name string, data []byte) error
+	Delete(name string) error
+	List() ([]string, error)
+}
+
+// FileBackend keeps one file per key in a directory only the current user can read
+type FileBackend struct {
+	dir string
+}
+
+// NewFileBackend creates dir if needed
+func NewFileBackend(dir string) (*FileBackend, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("can't create keystore directory: %w", err)
+	}
+	return &FileBackend{dir: dir}, nil
+}
+
+func (f *FileBackend) path(name string) (string, error) {
+	if !validName.MatchString(name) {
+		return "", fmt.Errorf("invalid key name %q", name)
+	}
+	return filepath.Join(f.dir, name+KEY_FILE_EXTENSION), nil
+}
+
+func (f *FileBackend) Load(name string) ([]byte, error) {
+	p, err := f.path(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return data, err
+}
+
+// Save writes to a temporary file and renames it so a crash never leaves a truncated key behind
+func (f *FileBackend) Save(name string, data []byte) error {
+	p, err := f.path(name)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(f.dir, "."+name+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (f *FileBackend) Delete(name string) error {
+	p, err := f.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (f *FileBackend) List() ([]string, error) {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), KEY_FILE_EXTENSION)
+		if e.Type().IsRegular() && name != e.Name() && validName.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
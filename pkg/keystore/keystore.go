@@ -0,0 +1,417 @@
+package keystore
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/configwizard/gaspump-api/pkg/signer"
+	wallet2 "github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	KEYSTORE_VERSION = 1
+	// DEFAULT_IDLE_TIMEOUT is how long an unlocked key stays in memory without being used
+	DEFAULT_IDLE_TIMEOUT = 5 * time.Minute
+	saltLen              = 32
+)
+
+// DefaultScrypt is stronger than the NEP-2 parameters as keys are only decrypted once per session
+var DefaultScrypt = keys.ScryptParams{N: 1 << 15, R: 8, P: 1}
+
+var (
+	ErrNotFound      = errors.New("key not found in keystore")
+	ErrExists        = errors.New("key already exists in keystore")
+	ErrLocked        = errors.New("key is locked")
+	ErrWrongPassword = errors.New("wrong password or corrupted key")
+)
+
+// sealedKey is what a Backend stores. The public key is kept in the clear, and authenticated, so a locked
+// key can still be listed and used to build requests
+type sealedKey struct {
+	Version    int               `json:"version"`
+	Address    string            `json:"address"`
+	PublicKey  []byte            `json:"publicKey"`
+	KDF        string            `json:"kdf"`
+	Scrypt     keys.ScryptParams `json:"scrypt"`
+	Salt       []byte            `json:"salt"`
+	Nonce      []byte            `json:"nonce"`
+	Ciphertext []byte            `json:"ciphertext"`
+}
+
+type unlockedKey struct {
+	key      *keys.PrivateKey
+	timer    *time.Timer
+	lastUsed time.Time
+}
+
+// Keystore encrypts keys with scrypt and AES-GCM before handing them to a Backend and keeps unlocked keys in memory
+// until they are locked or have been idle for the idle timeout. Locking zeroes the key
+type Keystore struct {
+	backend Backend
+	scrypt  keys.ScryptParams
+	idle    time.Duration
+
+	mu       sync.Mutex
+	unlocked map[string]*unlockedKey
+}
+
+func New(backend Backend) *Keystore {
+	return &Keystore{
+		backend:  backend,
+		scrypt:   DefaultScrypt,
+		idle:     DEFAULT_IDLE_TIMEOUT,
+		unlocked: make(map[string]*unlockedKey),
+	}
+}
+
+// NewFileKeystore keeps keys in dir
+func NewFileKeystore(dir string) (*Keystore, error) {
+	backend, err := NewFileBackend(dir)
+	if err != nil {
+		return nil, err
+	}
+	return New(backend), nil
+}
+
+// SetScryptParams changes the parameters used to seal keys from now on. Existing keys keep theirs
+func (k *Keystore) SetScryptParams(params keys.ScryptParams) {
+	k.scrypt = params
+}
+
+// SetIdleTimeout changes how long keys unlocked from now on stay unlocked without use. 0 disables the timeout
+func (k *Keystore) SetIdleTimeout(d time.Duration) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.idle = d
+}
+
+// Import seals key under name with password. The caller keeps ownership of key and password
+func (k *Keystore) Import(name string, key *ecdsa.PrivateKey, password []byte) error {
+	if len(password) == 0 {
+		return errors.New("password must not be empty")
+	}
+	if _, err := k.backend.Load(name); err == nil {
+		return fmt.Errorf("%w: %s", ErrExists, name)
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	plain := (&keys.PrivateKey{PrivateKey: *key}).Bytes()
+	defer ZeroBytes(plain)
+	data, err := k.seal(plain, password)
+	if err != nil {
+		return err
+	}
+	return k.backend.Save(name, data)
+}
+
+// Generate creates a new key under name and returns its public key
+func (k *Keystore) Generate(name string, password []byte) (*keys.PublicKey, error) {
+	key, err := keys.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	defer ZeroKey(&key.PrivateKey)
+	if err := k.Import(name, &key.PrivateKey, password); err != nil {
+		return nil, err
+	}
+	return key.PublicKey(), nil
+}
+
+// ImportWallet copies an account out of a neo-go wallet file into the keystore, see wallet.GetCredentialsFromPath
+func (k *Keystore) ImportWallet(name, walletPath, address, walletPassword string, password []byte) error {
+	key, err := wallet2.GetCredentialsFromPath(walletPath, address, walletPassword)
+	if err != nil {
+		return err
+	}
+	defer ZeroKey(key)
+	return k.Import(name, key, password)
+}
+
+// Names lists the keys in the keystore
+func (k *Keystore) Names() ([]string, error) {
+	return k.backend.List()
+}
+
+// PublicKey returns the public key stored under name without unlocking it
+func (k *Keystore) PublicKey(name string) (*keys.PublicKey, error) {
+	s, err := k.load(name)
+	if err != nil {
+		return nil, err
+	}
+	return keys.NewPublicKeyFromBytes(s.PublicKey, elliptic.P256())
+}
+
+// Remove locks and deletes the key
+func (k *Keystore) Remove(name string) error {
+	k.Lock(name)
+	return k.backend.Delete(name)
+}
+
+// ChangePassword re-seals the key with newPassword and the current scrypt parameters
+func (k *Keystore) ChangePassword(name string, oldPassword, newPassword []byte) error {
+	if len(newPassword) == 0 {
+		return errors.New("password must not be empty")
+	}
+	s, err := k.load(name)
+	if err != nil {
+		return err
+	}
+	plain, err := open(s, oldPassword)
+	if err != nil {
+		return err
+	}
+	defer ZeroBytes(plain)
+	data, err := k.seal(plain, newPassword)
+	if err != nil {
+		return err
+	}
+	return k.backend.Save(name, data)
+}
+
+// Unlock decrypts the key and keeps it in memory until Lock, LockAll or the idle timeout
+func (k *Keystore) Unlock(name string, password []byte) error {
+	s, err := k.load(name)
+	if err != nil {
+		return err
+	}
+	plain, err := open(s, password)
+	if err != nil {
+		return err
+	}
+	key, err := keys.NewPrivateKeyFromBytes(plain)
+	ZeroBytes(plain)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.lock(name)
+	entry := &unlockedKey{key: key, lastUsed: time.Now()}
+	if k.idle > 0 {
+		idle := k.idle
+		entry.timer = time.AfterFunc(idle, func() { k.expire(name, entry, idle) })
+	}
+	k.unlocked[name] = entry
+	return nil
+}
+
+// expire locks the key if it really has been idle; the timer may have fired while a use was resetting it
+func (k *Keystore) expire(name string, entry *unlockedKey, idle time.Duration) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.unlocked[name] != entry {
+		return
+	}
+	if remaining := idle - time.Since(entry.lastUsed); remaining > 0 {
+		entry.timer.Reset(remaining)
+		return
+	}
+	k.lock(name)
+}
+
+// IsUnlocked reports whether the key is currently in memory
+func (k *Keystore) IsUnlocked(name string) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	_, ok := k.unlocked[name]
+	return ok
+}
+
+// Lock zeroes the key and forgets it
+func (k *Keystore) Lock(name string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.lock(name)
+}
+
+// LockAll locks every key, e.g. when the application shuts down
+func (k *Keystore) LockAll() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for name := range k.unlocked {
+		k.lock(name)
+	}
+}
+
+func (k *Keystore) lock(name string) {
+	entry, ok := k.unlocked[name]
+	if !ok {
+		return
+	}
+	if entry.timer != nil {
+		entry.timer.Stop()
+	}
+	ZeroKey(&entry.key.PrivateKey)
+	delete(k.unlocked, name)
+}
+
+// use runs fn with the unlocked key and counts as activity for the idle timeout.
+// fn must not keep the key, it is zeroed when the keystore locks
+func (k *Keystore) use(name string, fn func(key *keys.PrivateKey) error) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	entry, ok := k.unlocked[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrLocked, name)
+	}
+	entry.lastUsed = time.Now()
+	return fn(entry.key)
+}
+
+// Credentials returns a copy of an unlocked key for APIs that need an *ecdsa.PrivateKey, such as the NeoFS client.
+// The copy is not zeroed when the keystore locks; call ZeroKey on it once it is no longer needed.
+// Prefer Signer where possible
+func (k *Keystore) Credentials(name string) (*ecdsa.PrivateKey, error) {
+	var key *keys.PrivateKey
+	err := k.use(name, func(unlocked *keys.PrivateKey) error {
+		b := unlocked.Bytes()
+		defer ZeroBytes(b)
+		var err error
+		key, err = keys.NewPrivateKeyFromBytes(b)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &key.PrivateKey, nil
+}
+
+// Signer returns a signer.Signer for the key. It can be created while the key is locked, signing fails until it is unlocked
+func (k *Keystore) Signer(name string) (*Signer, error) {
+	pub, err := k.PublicKey(name)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{keystore: k, name: name, publicKey: pub}, nil
+}
+
+// Signer signs with a keystore key without the key ever leaving the keystore
+type Signer struct {
+	keystore  *Keystore
+	name      string
+	publicKey *keys.PublicKey
+}
+
+func (s *Signer) PublicKey() *keys.PublicKey {
+	return s.publicKey
+}
+
+func (s *Signer) Sign(_ context.Context, scheme signer.Scheme, payload []byte) ([]byte, error) {
+	var sig []byte
+	err := s.keystore.use(s.name, func(key *keys.PrivateKey) error {
+		var err error
+		sig, err = signer.Sign(key, scheme, payload)
+		return err
+	})
+	return sig, err
+}
+
+// GetCredentials reads a single key from the keystore in dir, the keystore counterpart of wallet.GetCredentialsFromPath.
+// password is zeroed once used
+func GetCredentials(dir, name string, password []byte) (*ecdsa.PrivateKey, error) {
+	defer ZeroBytes(password)
+	k, err := NewFileKeystore(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := k.Unlock(name, password); err != nil {
+		return nil, err
+	}
+	defer k.Lock(name)
+	return k.Credentials(name)
+}
+
+func (k *Keystore) load(name string) (*sealedKey, error) {
+	data, err := k.backend.Load(name)
+	if err != nil {
+		return nil, err
+	}
+	s := new(sealedKey)
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("can't read key %s: %w", name, err)
+	}
+	if s.Version != KEYSTORE_VERSION || s.KDF != "scrypt" {
+		return nil, fmt.Errorf("unsupported key format for %s: version %d, kdf %q", name, s.Version, s.KDF)
+	}
+	return s, nil
+}
+
+func (k *Keystore) seal(plain, password []byte) ([]byte, error) {
+	key, err := keys.NewPrivateKeyFromBytes(plain)
+	if err != nil {
+		return nil, err
+	}
+	defer ZeroKey(&key.PrivateKey)
+	s := &sealedKey{
+		Version:   KEYSTORE_VERSION,
+		Address:   key.Address(),
+		PublicKey: key.PublicKey().Bytes(),
+		KDF:       "scrypt",
+		Scrypt:    k.scrypt,
+		Salt:      make([]byte, saltLen),
+	}
+	if _, err := rand.Read(s.Salt); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(password, s.Salt, s.Scrypt)
+	if err != nil {
+		return nil, err
+	}
+	s.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(s.Nonce); err != nil {
+		return nil, err
+	}
+	s.Ciphertext = aead.Seal(nil, s.Nonce, plain, s.PublicKey)
+	return json.Marshal(s)
+}
+
+func open(s *sealedKey, password []byte) ([]byte, error) {
+	aead, err := newAEAD(password, s.Salt, s.Scrypt)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Nonce) != aead.NonceSize() {
+		return nil, ErrWrongPassword
+	}
+	plain, err := aead.Open(nil, s.Nonce, s.Ciphertext, s.PublicKey)
+	if err != nil {
+		return nil, ErrWrongPassword
+	}
+	key, err := keys.NewPrivateKeyFromBytes(plain)
+	if err != nil {
+		ZeroBytes(plain)
+		return nil, err
+	}
+	defer ZeroKey(&key.PrivateKey)
+	pub := key.PublicKey().Bytes()
+	if string(pub) != string(s.PublicKey) {
+		ZeroBytes(plain)
+		return nil, ErrWrongPassword
+	}
+	return plain, nil
+}
+
+func newAEAD(password, salt []byte, params keys.ScryptParams) (cipher.AEAD, error) {
+	derived, err := scrypt.Key(password, salt, params.N, params.R, params.P, 32)
+	if err != nil {
+		return nil, err
+	}
+	defer ZeroBytes(derived)
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,119 @@
+package keystore_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/configwizard/gaspump-api/pkg/keystore"
+	"github.com/configwizard/gaspump-api/pkg/signer"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/stretchr/testify/assert"
+)
+
+var testScrypt = keys.ScryptParams{N: 16, R: 1, P: 1}
+
+func newKeystore(t *testing.T) (*keystore.Keystore, string) {
+	dir := filepath.Join(t.TempDir(), "keys")
+	k, err := keystore.NewFileKeystore(dir)
+	assert.Nil(t, err, "error not nil")
+	k.SetScryptParams(testScrypt)
+	return k, dir
+}
+
+func TestKeystore(t *testing.T) {
+	k, dir := newKeystore(t)
+	key, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+
+	assert.Nil(t, k.Import("main", &key.PrivateKey, []byte("password")), "error not nil")
+	assert.True(t, errors.Is(k.Import("main", &key.PrivateKey, []byte("password")), keystore.ErrExists))
+	assert.NotNil(t, k.Import("../escape", &key.PrivateKey, []byte("password")), "accepted a path as a name")
+
+	info, err := os.Stat(filepath.Join(dir, "main"+keystore.KEY_FILE_EXTENSION))
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	names, err := k.Names()
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, []string{"main"}, names)
+	pub, err := k.PublicKey("main")
+	assert.Nil(t, err, "error not nil")
+	assert.True(t, pub.Equal(key.PublicKey()))
+
+	_, err = k.Credentials("main")
+	assert.True(t, errors.Is(err, keystore.ErrLocked))
+	assert.True(t, errors.Is(k.Unlock("main", []byte("wrong")), keystore.ErrWrongPassword))
+	assert.True(t, errors.Is(k.Unlock("missing", []byte("password")), keystore.ErrNotFound))
+	assert.Nil(t, k.Unlock("main", []byte("password")), "error not nil")
+
+	creds, err := k.Credentials("main")
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, key.PrivateKey.D, creds.D)
+	keystore.ZeroKey(creds)
+	assert.Equal(t, 0, creds.D.Sign())
+
+	s, err := k.Signer("main")
+	assert.Nil(t, err, "error not nil")
+	payload := []byte("payload")
+	sig, err := s.Sign(context.Background(), signer.SchemeSHA512, payload)
+	assert.Nil(t, err, "error not nil")
+	assert.Nil(t, signer.Verify(s.PublicKey().Bytes(), signer.SchemeSHA512, payload, sig), "error not nil")
+
+	k.LockAll()
+	assert.False(t, k.IsUnlocked("main"))
+	_, err = s.Sign(context.Background(), signer.SchemeSHA512, payload)
+	assert.True(t, errors.Is(err, keystore.ErrLocked))
+
+	assert.Nil(t, k.ChangePassword("main", []byte("password"), []byte("new")), "error not nil")
+	creds, err = keystore.GetCredentials(dir, "main", []byte("new"))
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, key.PrivateKey.D, creds.D)
+	_, err = keystore.GetCredentials(dir, "main", []byte("password"))
+	assert.True(t, errors.Is(err, keystore.ErrWrongPassword))
+
+	assert.Nil(t, k.Remove("main"), "error not nil")
+	assert.True(t, errors.Is(k.Remove("main"), keystore.ErrNotFound))
+}
+
+func TestIdleTimeout(t *testing.T) {
+	k, _ := newKeystore(t)
+	k.SetIdleTimeout(100 * time.Millisecond)
+	_, err := k.Generate("session", []byte("password"))
+	assert.Nil(t, err, "error not nil")
+	assert.Nil(t, k.Unlock("session", []byte("password")), "error not nil")
+
+	// each use pushes the timeout back
+	for i := 0; i < 4; i++ {
+		time.Sleep(50 * time.Millisecond)
+		_, err := k.Credentials("session")
+		assert.Nil(t, err, "key locked while in use")
+	}
+	assert.Eventually(t, func() bool { return !k.IsUnlocked("session") }, time.Second, 10*time.Millisecond)
+}
+
+func TestTamperedKey(t *testing.T) {
+	k, dir := newKeystore(t)
+	_, err := k.Generate("main", []byte("password"))
+	assert.Nil(t, err, "error not nil")
+
+	// swapping the stored public key must break the authentication of the ciphertext
+	backend, err := keystore.NewFileBackend(dir)
+	assert.Nil(t, err, "error not nil")
+	data, err := backend.Load("main")
+	assert.Nil(t, err, "error not nil")
+	var sealed map[string]interface{}
+	assert.Nil(t, json.Unmarshal(data, &sealed), "error not nil")
+	other, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	sealed["publicKey"] = other.PublicKey().Bytes()
+	data, err = json.Marshal(sealed)
+	assert.Nil(t, err, "error not nil")
+	assert.Nil(t, backend.Save("main", data), "error not nil")
+
+	assert.True(t, errors.Is(k.Unlock("main", []byte("password")), keystore.ErrWrongPassword))
+}
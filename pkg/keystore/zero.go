@@ -0,0 +1,30 @@
+package keystore
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+)
+
+// ZeroBytes overwrites b, e.g. a password once it has been used
+func ZeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// ZeroKey overwrites the private scalar of key in place. The key is unusable afterwards.
+// Callers that take a key out of the keystore with Credentials should call this when done with it
+func ZeroKey(key *ecdsa.PrivateKey) {
+	if key == nil || key.D == nil {
+		return
+	}
+	zeroInt(key.D)
+}
+
+func zeroInt(i *big.Int) {
+	words := i.Bits()
+	for j := range words {
+		words[j] = 0
+	}
+	i.SetInt64(0)
+}
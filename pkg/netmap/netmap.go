@@ -0,0 +1,264 @@
+package netmap
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	container2 "github.com/configwizard/gaspump-api/pkg/container"
+	"github.com/configwizard/gaspump-api/pkg/contract"
+	"github.com/configwizard/gaspump-api/pkg/nns"
+	wallet2 "github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neofs-sdk-go/client"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/netmap"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+)
+
+// NETMAP_CONTRACT is the NNS domain of the netmap contract on NeoFS chains
+const NETMAP_CONTRACT = "netmap.neofs"
+
+// storageNode is how the netmap contract stores a node, its NodeInfo in protobuf
+type storageNode struct {
+	Info []byte
+}
+
+// Explorer reads the network map from the netmap contract. The NeoFS API has no call for it,
+// storage nodes read it from the NeoFS chain too
+type Explorer struct {
+	contract *contract.Contract
+}
+
+// NewExplorer finds the netmap contract with r, rpc and r must be connected to a NeoFS chain node
+func NewExplorer(ctx context.Context, rpc *wallet2.RPCClient, r *nns.Resolver) (*Explorer, error) {
+	hash, err := r.ResolveContract(ctx, NETMAP_CONTRACT)
+	if err != nil {
+		return nil, fmt.Errorf("can't find netmap contract: %w", err)
+	}
+	return NewExplorerAt(rpc, hash), nil
+}
+
+func NewExplorerAt(rpc *wallet2.RPCClient, hash util.Uint160) *Explorer {
+	return &Explorer{contract: contract.New(rpc, hash)}
+}
+
+func (e *Explorer) Hash() util.Uint160 {
+	return e.contract.Hash()
+}
+
+// Epoch returns the current epoch, the one the netmap of Snapshot is for
+func (e *Explorer) Epoch(ctx context.Context) (uint64, error) {
+	var epoch uint64
+	if err := e.contract.Call(ctx, "epoch", &epoch); err != nil {
+		return 0, err
+	}
+	return epoch, nil
+}
+
+// Snapshot returns the nodes of the current netmap, the nodes containers are placed on this epoch
+func (e *Explorer) Snapshot(ctx context.Context) ([]netmap.NodeInfo, error) {
+	var nodes []storageNode
+	if err := e.contract.Call(ctx, "netmap", &nodes); err != nil {
+		return nil, err
+	}
+	return decodeNodes(nodes)
+}
+
+// PreviousSnapshot returns the netmap of diff epochs ago, the contract keeps a few of them
+func (e *Explorer) PreviousSnapshot(ctx context.Context, diff int) ([]netmap.NodeInfo, error) {
+	var nodes []storageNode
+	if err := e.contract.Call(ctx, "snapshot", &nodes, diff); err != nil {
+		return nil, err
+	}
+	return decodeNodes(nodes)
+}
+
+func decodeNodes(nodes []storageNode) ([]netmap.NodeInfo, error) {
+	infos := make([]netmap.NodeInfo, len(nodes))
+	for i := range nodes {
+		if err := infos[i].Unmarshal(nodes[i].Info); err != nil {
+			return nil, fmt.Errorf("invalid node info in netmap: %w", err)
+		}
+	}
+	return infos, nil
+}
+
+// ContainerPlacement returns the nodes of the current netmap a container is stored on
+func (e *Explorer) ContainerPlacement(ctx context.Context, cli *client.Client, containerID cid.ID) (*Placement, error) {
+	nodes, p, err := e.containerPolicy(ctx, cli, containerID)
+	if err != nil {
+		return nil, err
+	}
+	return ContainerPlacement(nodes, p, containerID)
+}
+
+// ObjectPlacement returns the nodes of the current netmap the replicas of an object are stored on
+func (e *Explorer) ObjectPlacement(ctx context.Context, cli *client.Client, containerID cid.ID, objectID oid.ID) (*Placement, error) {
+	nodes, p, err := e.containerPolicy(ctx, cli, containerID)
+	if err != nil {
+		return nil, err
+	}
+	return ObjectPlacement(nodes, p, containerID, objectID)
+}
+
+func (e *Explorer) containerPolicy(ctx context.Context, cli *client.Client, containerID cid.ID) ([]netmap.NodeInfo, *netmap.PlacementPolicy, error) {
+	cnr, err := container2.Get(ctx, cli, containerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	nodes, err := e.Snapshot(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nodes, cnr.PlacementPolicy(), nil
+}
+
+// LocalNode returns the storage node cli is connected to, as it describes itself
+func LocalNode(ctx context.Context, cli *client.Client) (*Node, error) {
+	res, err := cli.EndpointInfo(ctx, client.PrmEndpointInfo{})
+	if err != nil {
+		return nil, fmt.Errorf("can't get node info: %w", err)
+	}
+	node := NewNode(res.NodeInfo())
+	return &node, nil
+}
+
+// Node describes a storage node for diagnostics
+type Node struct {
+	PublicKey  string            `json:"publicKey"`
+	Addresses  []string          `json:"addresses"`
+	State      string            `json:"state"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+func NewNode(info *netmap.NodeInfo) Node {
+	n := Node{
+		PublicKey:  hex.EncodeToString(info.PublicKey()),
+		State:      info.State().String(),
+		Attributes: make(map[string]string, len(info.Attributes())),
+	}
+	netmap.IterateAllAddresses(info, func(addr string) {
+		n.Addresses = append(n.Addresses, addr)
+	})
+	for _, a := range info.Attributes() {
+		n.Attributes[a.Key()] = a.Value()
+	}
+	return n
+}
+
+// Nodes describes every node of a netmap
+func Nodes(infos []netmap.NodeInfo) []Node {
+	nodes := make([]Node, len(infos))
+	for i := range infos {
+		nodes[i] = NewNode(&infos[i])
+	}
+	return nodes
+}
+
+// String is one line: key, state, addresses and the attributes sorted by key
+func (n Node) String() string {
+	keys := make([]string, 0, len(n.Attributes))
+	for k := range n.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attributes := make([]string, len(keys))
+	for i, k := range keys {
+		attributes[i] = k + "=" + n.Attributes[k]
+	}
+	return fmt.Sprintf("%s %s %s %s", n.PublicKey, n.State, strings.Join(n.Addresses, ","), strings.Join(attributes, " "))
+}
+
+// Replica is where one REP statement of a placement policy stores copies
+type Replica struct {
+	Count    uint32 `json:"count"`
+	Selector string `json:"selector,omitempty"`
+	// Nodes selected for the replica. For an object they are in the order they are tried,
+	// the first Count hold the copies and the rest stand in for them
+	Nodes []Node `json:"nodes"`
+}
+
+// Placement is where a container, or an object of it, is stored
+type Placement struct {
+	Container string    `json:"container"`
+	Object    string    `json:"object,omitempty"`
+	Replicas  []Replica `json:"replicas"`
+}
+
+// ContainerPlacement selects the nodes of a netmap, e.g. a Snapshot, that policy p places container containerID on.
+// It fails like the network would if there are not enough nodes for the policy
+func ContainerPlacement(nodes []netmap.NodeInfo, p *netmap.PlacementPolicy, containerID cid.ID) (*Placement, error) {
+	_, containerNodes, err := containerNodes(nodes, p, containerID)
+	if err != nil {
+		return nil, err
+	}
+	return newPlacement(p, containerID, containerNodes.Replicas()), nil
+}
+
+// ObjectPlacement selects the nodes of a netmap, e.g. a Snapshot, the replicas of an object are stored on
+func ObjectPlacement(nodes []netmap.NodeInfo, p *netmap.PlacementPolicy, containerID cid.ID, objectID oid.ID) (*Placement, error) {
+	nm, containerNodes, err := containerNodes(nodes, p, containerID)
+	if err != nil {
+		return nil, err
+	}
+	vectors, err := nm.GetPlacementVectors(containerNodes, objectID.ToV2().GetValue())
+	if err != nil {
+		return nil, fmt.Errorf("can't place object %s: %w", &objectID, err)
+	}
+	placement := newPlacement(p, containerID, vectors)
+	placement.Object = objectID.String()
+	return placement, nil
+}
+
+func containerNodes(nodes []netmap.NodeInfo, p *netmap.PlacementPolicy, containerID cid.ID) (*netmap.Netmap, netmap.ContainerNodes, error) {
+	if p == nil {
+		return nil, nil, fmt.Errorf("container %s has no placement policy", &containerID)
+	}
+	nm, err := netmap.NewNetmap(netmap.NodesFromInfo(nodes))
+	if err != nil {
+		return nil, nil, err
+	}
+	containerNodes, err := nm.GetContainerNodes(p, containerID.ToV2().GetValue())
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't place container %s: %w", &containerID, err)
+	}
+	return nm, containerNodes, nil
+}
+
+func newPlacement(p *netmap.PlacementPolicy, containerID cid.ID, replicas []netmap.Nodes) *Placement {
+	placement := &Placement{Container: containerID.String(), Replicas: make([]Replica, len(replicas))}
+	for i, r := range p.Replicas() {
+		replica := Replica{Count: r.Count(), Selector: r.Selector(), Nodes: make([]Node, len(replicas[i]))}
+		for j, n := range replicas[i] {
+			replica.Nodes[j] = NewNode(n.NodeInfo)
+		}
+		placement.Replicas[i] = replica
+	}
+	return placement
+}
+
+// String lists the nodes of every replica, the nodes holding object copies marked with *
+func (p Placement) String() string {
+	b := new(strings.Builder)
+	b.WriteString("container " + p.Container)
+	if p.Object != "" {
+		b.WriteString(" object " + p.Object)
+	}
+	for _, r := range p.Replicas {
+		fmt.Fprintf(b, "\nREP %d", r.Count)
+		if r.Selector != "" {
+			b.WriteString(" IN " + r.Selector)
+		}
+		for i, n := range r.Nodes {
+			mark := " "
+			if p.Object != "" && i < int(r.Count) {
+				mark = "*"
+			}
+			fmt.Fprintf(b, "\n%s %s", mark, n)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,121 @@
+package netmap_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/configwizard/gaspump-api/internal/rpctest"
+	netmap2 "github.com/configwizard/gaspump-api/pkg/netmap"
+	"github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/netmap"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+var netmapHash = util.Uint160{0x6e, 0x6d}
+
+func node(key byte, country string) netmap.NodeInfo {
+	var n netmap.NodeInfo
+	n.SetPublicKey([]byte{2, key})
+	n.SetAddresses("/dns4/st" + string('0'+key) + ".example/tcp/8080")
+	n.SetState(netmap.NodeStateOnline)
+	a := netmap.NewNodeAttribute()
+	a.SetKey(netmap.AttrCountry)
+	a.SetValue(country)
+	n.SetAttributes(a)
+	return n
+}
+
+// newTestChain serves a netmap contract with nodes in the current netmap
+func newTestChain(t *testing.T, epoch int64, nodes []netmap.NodeInfo) *rpctest.Node {
+	snapshot := make([]stackitem.Item, len(nodes))
+	for i := range nodes {
+		info, err := nodes[i].Marshal()
+		assert.Nil(t, err, "error not nil")
+		snapshot[i] = stackitem.NewStruct([]stackitem.Item{stackitem.NewByteArray(info)})
+	}
+	node := rpctest.NewNode(t, netmode.TestNet)
+	node.Handle("invokescript", rpctest.InvokeScript(t, func(contract util.Uint160, method string, _ []stackitem.Item) stackitem.Item {
+		assert.Equal(t, netmapHash, contract)
+		switch method {
+		case "epoch":
+			return stackitem.Make(epoch)
+		case "netmap":
+			return stackitem.NewArray(snapshot)
+		}
+		t.Errorf("unexpected method %s", method)
+		return stackitem.Null{}
+	}))
+	return node
+}
+
+func testNodes() []netmap.NodeInfo {
+	return []netmap.NodeInfo{node(1, "Germany"), node(2, "Germany"), node(3, "France"), node(4, "Japan")}
+}
+
+func TestExplorer(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestChain(t, 42, testNodes())
+	rpc, err := wallet.NewRPCClient(ctx, client.Options{}, wallet.RPC_NETWORK(srv.URL))
+	assert.Nil(t, err, "error not nil")
+
+	e := netmap2.NewExplorerAt(rpc, netmapHash)
+	epoch, err := e.Epoch(ctx)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, uint64(42), epoch)
+
+	nodes, err := e.Snapshot(ctx)
+	assert.Nil(t, err, "error not nil")
+	assert.Len(t, nodes, 4)
+	described := netmap2.Nodes(nodes)
+	assert.Equal(t, netmap2.Node{
+		PublicKey:  "0203",
+		Addresses:  []string{"/dns4/st3.example/tcp/8080"},
+		State:      "ONLINE",
+		Attributes: map[string]string{netmap.AttrCountry: "France"},
+	}, described[2])
+	assert.Equal(t, "0203 ONLINE /dns4/st3.example/tcp/8080 Country=France", described[2].String())
+}
+
+func TestPlacement(t *testing.T) {
+	p, err := policy.Parse("REP 1 IN X\nCBF 1\nSELECT 2 IN DISTINCT Country FROM * AS X")
+	assert.Nil(t, err, "error not nil")
+	containerID := cid.New()
+	containerID.SetSHA256(sha256.Sum256([]byte("container")))
+	objectID := oid.NewID()
+	objectID.SetSHA256(sha256.Sum256([]byte("object")))
+
+	placement, err := netmap2.ContainerPlacement(testNodes(), p, *containerID)
+	assert.Nil(t, err, "error not nil")
+	assert.Len(t, placement.Replicas, 1)
+	assert.Equal(t, "X", placement.Replicas[0].Selector)
+	nodes := placement.Replicas[0].Nodes
+	assert.Len(t, nodes, 2)
+	assert.NotEqual(t, nodes[0].Attributes[netmap.AttrCountry], nodes[1].Attributes[netmap.AttrCountry])
+
+	objectPlacement, err := netmap2.ObjectPlacement(testNodes(), p, *containerID, *objectID)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, objectID.String(), objectPlacement.Object)
+	assert.ElementsMatch(t, nodes, objectPlacement.Replicas[0].Nodes)
+	again, err := netmap2.ObjectPlacement(testNodes(), p, *containerID, *objectID)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, objectPlacement, again)
+
+	lines := strings.Split(objectPlacement.String(), "\n")
+	assert.Len(t, lines, 4)
+	assert.Equal(t, "REP 1 IN X", lines[1])
+	assert.True(t, strings.HasPrefix(lines[2], "* "+objectPlacement.Replicas[0].Nodes[0].PublicKey), "copy not marked")
+	assert.True(t, strings.HasPrefix(lines[3], "  "), "stand in marked")
+
+	_, err = netmap2.ContainerPlacement(testNodes()[:2], p, *containerID)
+	assert.True(t, errors.Is(err, netmap.ErrNotEnoughNodes), "placed on a single country")
+}
@@ -0,0 +1,209 @@
+package nns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	wallet2 "github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client/nns"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	v2container "github.com/nspcc-dev/neofs-api-go/v2/container"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+)
+
+const (
+	// NNS_CONTRACT_ID is the id of the NNS contract on NeoFS chains, where it is the first contract deployed
+	NNS_CONTRACT_ID = 1
+	// CONTAINER_ZONE is the zone container names are registered in, so a container named photos is photos.container
+	CONTAINER_ZONE = v2container.SysAttributeZoneDefault
+)
+
+type RecordType = nns.RecordType
+
+const (
+	RECORD_A     = nns.A
+	RECORD_CNAME = nns.CNAME
+	RECORD_TXT   = nns.TXT
+	RECORD_AAAA  = nns.AAAA
+)
+
+// ErrNotFound is returned when a name has no record of the type looked for
+var ErrNotFound = errors.New("name not found")
+
+// Resolver looks names up in an NNS contract
+type Resolver struct {
+	rpc  *wallet2.RPCClient
+	hash util.Uint160
+}
+
+// NewResolver finds the NNS contract of the chain rpc is connected to. Container names live on the NeoFS chain,
+// so rpc should be connected to a NeoFS chain node to resolve them
+func NewResolver(ctx context.Context, rpc *wallet2.RPCClient) (*Resolver, error) {
+	var cs *state.Contract
+	err := rpc.Do(ctx, func(cli *client.Client) (err error) {
+		cs, err = cli.GetContractStateByID(NNS_CONTRACT_ID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't find NNS contract: %w", err)
+	}
+	return NewResolverAt(rpc, cs.Hash), nil
+}
+
+// NewResolverAt uses the NNS contract at hash, e.g. a name service deployed on the main chain
+func NewResolverAt(rpc *wallet2.RPCClient, hash util.Uint160) *Resolver {
+	return &Resolver{rpc: rpc, hash: hash}
+}
+
+func (r *Resolver) Hash() util.Uint160 {
+	return r.hash
+}
+
+// Records returns the records of type typ for name, following CNAMEs. NeoFS NNS returns every record,
+// other name services may only return the first
+func (r *Resolver) Records(ctx context.Context, name string, typ RecordType) ([]string, error) {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	var res *wallet2.InvokeResult
+	err := r.rpc.Do(ctx, func(cli *client.Client) (err error) {
+		res, err = wallet2.NewTxBuilder(cli).Call(r.hash, "resolve", name, int64(typ)).TestInvoke()
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't resolve %s: %w", name, err)
+	}
+	item, err := res.Item(0)
+	if err != nil {
+		return nil, fmt.Errorf("can't resolve %s: %w", name, err)
+	}
+	var records []string
+	switch v := item.(type) {
+	case stackitem.Null:
+	case *stackitem.Array, *stackitem.Struct:
+		if err := wallet2.FromStackItem(v, &records); err != nil {
+			return nil, fmt.Errorf("invalid records of %s: %w", name, err)
+		}
+	default:
+		b, err := item.TryBytes()
+		if err != nil {
+			return nil, fmt.Errorf("invalid record of %s: %w", name, err)
+		}
+		records = []string{string(b)}
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return records, nil
+}
+
+// IsAvailable reports whether name can be registered. It fails if the name's parent domain is not registered
+func (r *Resolver) IsAvailable(ctx context.Context, name string) (bool, error) {
+	var available bool
+	err := r.rpc.Do(ctx, func(cli *client.Client) (err error) {
+		available, err = cli.NNSIsAvailable(r.hash, strings.ToLower(name))
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("can't check %s: %w", name, err)
+	}
+	return available, nil
+}
+
+// ResolveAddress returns the address in the TXT records of name
+func (r *Resolver) ResolveAddress(ctx context.Context, name string) (util.Uint160, error) {
+	records, err := r.Records(ctx, name, RECORD_TXT)
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	for _, rec := range records {
+		if u, err := wallet2.StringToUint160(rec); err == nil {
+			return u, nil
+		}
+	}
+	return util.Uint160{}, fmt.Errorf("%w: %s has no address record", ErrNotFound, name)
+}
+
+// ResolveContainer returns the container in the TXT records of name. A name without a zone, e.g. photos,
+// is looked up in the container zone
+func (r *Resolver) ResolveContainer(ctx context.Context, name string) (*cid.ID, error) {
+	records, err := r.Records(ctx, ContainerDomain(name, ""), RECORD_TXT)
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range records {
+		id := cid.New()
+		if err := id.Parse(rec); err == nil {
+			return id, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s has no container record", ErrNotFound, name)
+}
+
+// ResolveContract returns the contract hash in the TXT records of name, e.g. netmap.neofs which NeoFS chains
+// register their contracts under. Hashes are stored as little endian hex or as addresses
+func (r *Resolver) ResolveContract(ctx context.Context, name string) (util.Uint160, error) {
+	records, err := r.Records(ctx, name, RECORD_TXT)
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	for _, rec := range records {
+		if u, err := util.Uint160DecodeStringLE(rec); err == nil {
+			return u, nil
+		}
+		if u, err := wallet2.StringToUint160(rec); err == nil {
+			return u, nil
+		}
+	}
+	return util.Uint160{}, fmt.Errorf("%w: %s has no contract record", ErrNotFound, name)
+}
+
+// Address accepts either an address or a name to resolve, so users can share whichever they have
+func (r *Resolver) Address(ctx context.Context, addressOrName string) (util.Uint160, error) {
+	if u, err := wallet2.StringToUint160(addressOrName); err == nil {
+		return u, nil
+	}
+	return r.ResolveAddress(ctx, addressOrName)
+}
+
+// Container accepts either a container ID or a container name to resolve
+func (r *Resolver) Container(ctx context.Context, idOrName string) (*cid.ID, error) {
+	id := cid.New()
+	if err := id.Parse(idOrName); err == nil {
+		return id, nil
+	}
+	return r.ResolveContainer(ctx, idOrName)
+}
+
+// ReverseContainer returns the domain of a container created with name and zone, the values of its __NEOFS__NAME
+// and __NEOFS__ZONE attributes. The domain is resolved to check it still points at the container,
+// ErrNotFound is returned if it doesn't or the container has no name
+func (r *Resolver) ReverseContainer(ctx context.Context, id *cid.ID, name, zone string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("%w: container %s has no name", ErrNotFound, id)
+	}
+	domain := ContainerDomain(name, zone)
+	resolved, err := r.ResolveContainer(ctx, domain)
+	if err != nil {
+		return "", err
+	}
+	if !resolved.Equal(id) {
+		return "", fmt.Errorf("%w: %s belongs to container %s", ErrNotFound, domain, resolved)
+	}
+	return domain, nil
+}
+
+// ContainerDomain is the domain a container name is registered as, name.zone. An empty zone is the container zone,
+// a name that already has a zone is returned as it is
+func ContainerDomain(name, zone string) string {
+	if strings.Contains(name, ".") {
+		return name
+	}
+	if zone == "" {
+		zone = CONTAINER_ZONE
+	}
+	return name + "." + zone
+}
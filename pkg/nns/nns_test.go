@@ -0,0 +1,142 @@
+package nns_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/configwizard/gaspump-api/internal/rpctest"
+	"github.com/configwizard/gaspump-api/pkg/nns"
+	"github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/stretchr/testify/assert"
+)
+
+var nnsHash = util.Uint160{0x4e, 0x4e, 0x53}
+
+// newTestChain serves an NNS contract holding records. If single is set resolve returns one string
+// like main chain name services do, otherwise an array like NeoFS NNS does
+func newTestChain(t *testing.T, records map[string][]string, single bool) *rpctest.Node {
+	resolve := func(name string) stackitem.Item {
+		recs, ok := records[name]
+		switch {
+		case !ok:
+			return stackitem.Null{}
+		case single:
+			return stackitem.Make(recs[0])
+		}
+		items := make([]stackitem.Item, len(recs))
+		for i := range recs {
+			items[i] = stackitem.Make(recs[i])
+		}
+		return stackitem.NewArray(items)
+	}
+
+	node := rpctest.NewNode(t, netmode.TestNet)
+	node.AddContract(rpctest.NewContract(t, "NameService", 1, nnsHash))
+	node.Handle("invokefunction", func(params []interface{}) (interface{}, error) {
+		assert.Equal(t, "isAvailable", params[1].(string))
+		name := params[2].([]interface{})[0].(map[string]interface{})["value"].(string)
+		_, taken := records[name]
+		return result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.NewBool(!taken)}}, nil
+	})
+	node.Handle("invokescript", rpctest.InvokeScript(t, func(contract util.Uint160, method string, args []stackitem.Item) stackitem.Item {
+		assert.Equal(t, nnsHash, contract)
+		assert.Equal(t, "resolve", method)
+		name, err := args[0].TryBytes()
+		assert.Nil(t, err, "error not nil")
+		return resolve(string(name))
+	}))
+	return node
+}
+
+func containerID(seed string) *cid.ID {
+	id := cid.New()
+	id.SetSHA256(sha256.Sum256([]byte(seed)))
+	return id
+}
+
+func TestResolver(t *testing.T) {
+	ctx := context.Background()
+	photos, other := containerID("photos"), containerID("other")
+	owner := "NX8GreRFGFK5wpGMWetpX93HmtrezGogzk"
+	srv := newTestChain(t, map[string][]string{
+		"photos.container": {"not an id", photos.String()},
+		"moved.container":  {other.String()},
+		"alice.neo":        {owner},
+		"netmap.neofs":     {nnsHash.StringLE()},
+		"audit.neofs":      {wallet.Uint160ToString(nnsHash)},
+	}, false)
+	rpc, err := wallet.NewRPCClient(ctx, client.Options{}, wallet.RPC_NETWORK(srv.URL))
+	assert.Nil(t, err, "error not nil")
+	r, err := nns.NewResolver(ctx, rpc)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, nnsHash, r.Hash())
+
+	id, err := r.ResolveContainer(ctx, "photos")
+	assert.Nil(t, err, "error not nil")
+	assert.True(t, photos.Equal(id), "wrong container")
+	id, err = r.Container(ctx, "Photos.container.")
+	assert.Nil(t, err, "error not nil")
+	assert.True(t, photos.Equal(id), "wrong container")
+	id, err = r.Container(ctx, other.String())
+	assert.Nil(t, err, "error not nil")
+	assert.True(t, other.Equal(id), "ID not passed through")
+	_, err = r.ResolveContainer(ctx, "missing")
+	assert.True(t, errors.Is(err, nns.ErrNotFound), "missing name found")
+
+	addr, err := r.Address(ctx, "alice.neo")
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, owner, wallet.Uint160ToString(addr))
+	_, err = r.ResolveAddress(ctx, "photos.container")
+	assert.True(t, errors.Is(err, nns.ErrNotFound), "container ID taken for an address")
+
+	hash, err := r.ResolveContract(ctx, "netmap.neofs")
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, nnsHash, hash)
+	hash, err = r.ResolveContract(ctx, "audit.neofs")
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, nnsHash, hash)
+	_, err = r.ResolveContract(ctx, "photos.container")
+	assert.True(t, errors.Is(err, nns.ErrNotFound), "container ID taken for a contract")
+
+	domain, err := r.ReverseContainer(ctx, photos, "photos", "")
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, "photos.container", domain)
+	_, err = r.ReverseContainer(ctx, photos, "moved", "container")
+	assert.True(t, errors.Is(err, nns.ErrNotFound), "name of another container accepted")
+	_, err = r.ReverseContainer(ctx, photos, "", "")
+	assert.True(t, errors.Is(err, nns.ErrNotFound), "unnamed container has a name")
+
+	available, err := r.IsAvailable(ctx, "photos.container")
+	assert.Nil(t, err, "error not nil")
+	assert.False(t, available, "taken name available")
+	available, err = r.IsAvailable(ctx, "new.container")
+	assert.Nil(t, err, "error not nil")
+	assert.True(t, available, "free name taken")
+}
+
+func TestResolverSingleRecord(t *testing.T) {
+	ctx := context.Background()
+	owner := "NX8GreRFGFK5wpGMWetpX93HmtrezGogzk"
+	srv := newTestChain(t, map[string][]string{"alice.neo": {owner}}, true)
+	rpc, err := wallet.NewRPCClient(ctx, client.Options{}, wallet.RPC_NETWORK(srv.URL))
+	assert.Nil(t, err, "error not nil")
+
+	r := nns.NewResolverAt(rpc, nnsHash)
+	records, err := r.Records(ctx, "alice.neo", nns.RECORD_TXT)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, []string{owner}, records)
+}
+
+func TestContainerDomain(t *testing.T) {
+	assert.Equal(t, "photos.container", nns.ContainerDomain("photos", ""))
+	assert.Equal(t, "photos.team", nns.ContainerDomain("photos", "team"))
+	assert.Equal(t, "photos.team", nns.ContainerDomain("photos.team", "container"))
+}
@@ -169,15 +169,19 @@ func GetObject(ctx context.Context, cli *client.Client, payloadSize int, objectI
 	} else {
 		buf = make([]byte, 1024)
 		for {
-			_, err := objReader.Read(buf)
+			n, err := objReader.Read(buf)
 
 			// get total size from object header and update progress bar based on n bytes received
+			if n > 0 {
+				if _, writerErr := (*writer).Write(buf[:n]); writerErr != nil {
+					return nil, errors.New("error writing to buffer: " + writerErr.Error())
+				}
+			}
 			if errors.Is(err, io.EOF) {
 				fmt.Println("end of file")
 				break
-			}
-			if _, writerErr := (*writer).Write(buf); writerErr != nil {
-				return nil, errors.New("error writing to buffer: " + writerErr.Error())
+			} else if err != nil {
+				return dstObject, err
 			}
 		}
 	}
@@ -0,0 +1,407 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-sdk-go/netmap"
+	"github.com/nspcc-dev/neofs-sdk-go/policy"
+	subnetid "github.com/nspcc-dev/neofs-sdk-go/subnet/id"
+)
+
+const (
+	// CLAUSE_SAME selects all nodes of a selector from one bucket, e.g. one country
+	CLAUSE_SAME = netmap.ClauseSame
+	// CLAUSE_DISTINCT selects every node of a selector from another bucket
+	CLAUSE_DISTINCT = netmap.ClauseDistinct
+	// DEFAULT_BACKUP_FACTOR is the container backup factor of policies without CBF
+	DEFAULT_BACKUP_FACTOR = 3
+)
+
+// keys and values are quoted unless they are numbers or identifiers other than the words of the policy language
+var (
+	identifier = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	number     = regexp.MustCompile(`^(0|[1-9][0-9]*)$`)
+	reserved   = map[string]bool{"AND": true, "OR": true, "EQ": true, "NE": true, "GE": true, "GT": true,
+		"LT": true, "LE": true, "CBF": true, "SAME": true, "DISTINCT": true}
+)
+
+// Filter is a condition on the attributes of storage nodes
+type Filter struct {
+	key   string
+	value string
+	op    netmap.Operation
+	ref   string
+	inner []Filter
+}
+
+// Eq matches nodes whose attribute key is value
+func Eq(key, value string) Filter {
+	return Filter{key: key, value: value, op: netmap.OpEQ}
+}
+
+// Ne matches nodes whose attribute key is not value
+func Ne(key, value string) Filter {
+	return Filter{key: key, value: value, op: netmap.OpNE}
+}
+
+// Gt matches nodes whose numeric attribute key is greater than value
+func Gt(key string, value uint64) Filter {
+	return Filter{key: key, value: strconv.FormatUint(value, 10), op: netmap.OpGT}
+}
+
+// Ge matches nodes whose numeric attribute key is at least value
+func Ge(key string, value uint64) Filter {
+	return Filter{key: key, value: strconv.FormatUint(value, 10), op: netmap.OpGE}
+}
+
+// Lt matches nodes whose numeric attribute key is less than value
+func Lt(key string, value uint64) Filter {
+	return Filter{key: key, value: strconv.FormatUint(value, 10), op: netmap.OpLT}
+}
+
+// Le matches nodes whose numeric attribute key is at most value
+func Le(key string, value uint64) Filter {
+	return Filter{key: key, value: strconv.FormatUint(value, 10), op: netmap.OpLE}
+}
+
+// Country matches nodes in a country, by its English short name e.g. Germany
+func Country(name string) Filter {
+	return Eq(netmap.AttrCountry, name)
+}
+
+// CountryCode matches nodes in a country, by its ISO 3166-1 alpha-2 code e.g. DE
+func CountryCode(code string) Filter {
+	return Eq(netmap.AttrCountryCode, code)
+}
+
+// Continent matches nodes on a continent, e.g. Europe or North America
+func Continent(name string) Filter {
+	return Eq(netmap.AttrContinent, name)
+}
+
+// MaxPrice matches nodes storing a GB for an epoch for at most price
+func MaxPrice(price uint64) Filter {
+	return Le(netmap.AttrPrice, price)
+}
+
+// MinCapacity matches nodes with at least gb GB of space
+func MinCapacity(gb uint64) Filter {
+	return Ge(netmap.AttrCapacity, gb)
+}
+
+// And matches nodes matching all of fs
+func And(fs ...Filter) Filter {
+	return Filter{op: netmap.OpAND, inner: fs}
+}
+
+// Or matches nodes matching any of fs
+func Or(fs ...Filter) Filter {
+	return Filter{op: netmap.OpOR, inner: fs}
+}
+
+// Ref matches nodes matching the filter named name
+func Ref(name string) Filter {
+	return Filter{ref: name}
+}
+
+func (f Filter) String() string {
+	switch {
+	case f.ref != "":
+		return "@" + f.ref
+	case f.op == netmap.OpAND || f.op == netmap.OpOR:
+		parts := make([]string, len(f.inner))
+		for i, in := range f.inner {
+			parts[i] = in.String()
+			if len(in.inner) != 0 {
+				parts[i] = "(" + parts[i] + ")"
+			}
+		}
+		return strings.Join(parts, " "+f.op.String()+" ")
+	}
+	value := f.value
+	if !number.MatchString(value) {
+		value = quote(value)
+	}
+	return quote(f.key) + " " + f.op.String() + " " + value
+}
+
+func (f Filter) check() error {
+	for _, s := range []string{f.key, f.value} {
+		if strings.Contains(s, `"`) && strings.Contains(s, "'") {
+			return fmt.Errorf("%s can't be quoted in a policy", s)
+		}
+	}
+	for _, in := range f.inner {
+		if err := in.check(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// quote leaves identifiers as they are, so common policies read like the written ones
+func quote(s string) string {
+	switch {
+	case identifier.MatchString(s) && !reserved[strings.ToUpper(s)]:
+		return s
+	case strings.Contains(s, `"`):
+		return "'" + s + "'"
+	}
+	return `"` + s + `"`
+}
+
+// Selector picks Count nodes, or buckets of nodes, for replicas to be stored on
+type Selector struct {
+	// Name the replicas refer to
+	Name string
+	// Count of nodes, or of buckets if Attribute is set
+	Count uint32
+	// Clause how nodes are spread over buckets of the same Attribute value, CLAUSE_DISTINCT if zero
+	Clause netmap.Clause
+	// Attribute to group nodes by, e.g. Country, no grouping if empty
+	Attribute string
+	// Filter nodes are selected from, every node if empty
+	Filter string
+}
+
+func (s Selector) String() string {
+	b := new(strings.Builder)
+	b.WriteString("SELECT " + strconv.FormatUint(uint64(s.Count), 10))
+	if s.Attribute != "" {
+		b.WriteString(" IN ")
+		if s.Clause == CLAUSE_SAME {
+			b.WriteString("SAME ")
+		} else if s.Clause == CLAUSE_DISTINCT {
+			b.WriteString("DISTINCT ")
+		}
+		b.WriteString(s.Attribute)
+	}
+	filter := s.Filter
+	if filter == "" {
+		filter = netmap.MainFilterName
+	}
+	b.WriteString(" FROM " + filter)
+	if s.Name != "" {
+		b.WriteString(" AS " + s.Name)
+	}
+	return b.String()
+}
+
+type replica struct {
+	count    uint32
+	selector string
+}
+
+type namedFilter struct {
+	name   string
+	filter Filter
+}
+
+// Builder puts a placement policy together, e.g.
+//
+//	NewBuilder().
+//		Replicas(2, "EU").
+//		Select(Selector{Name: "EU", Count: 2, Attribute: netmap.AttrCountry, Filter: "CHEAP_EU"}).
+//		Filter("CHEAP_EU", And(Continent("Europe"), MaxPrice(10)))
+//
+// is REP 2 IN EU, SELECT 2 IN Country FROM CHEAP_EU AS EU, FILTER Continent EQ Europe AND Price LE 10 AS CHEAP_EU
+type Builder struct {
+	replicas  []replica
+	cbf       uint32
+	selectors []Selector
+	filters   []namedFilter
+}
+
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Replicas stores count copies of each object on the nodes of selector, or of any selector if it is empty
+func (b *Builder) Replicas(count uint32, selector string) *Builder {
+	b.replicas = append(b.replicas, replica{count: count, selector: selector})
+	return b
+}
+
+// BackupFactor selects cbf times the nodes the replicas need, so the container survives nodes leaving
+func (b *Builder) BackupFactor(cbf uint32) *Builder {
+	b.cbf = cbf
+	return b
+}
+
+func (b *Builder) Select(s Selector) *Builder {
+	b.selectors = append(b.selectors, s)
+	return b
+}
+
+// Filter names f for selectors and other filters to use
+func (b *Builder) Filter(name string, f Filter) *Builder {
+	b.filters = append(b.filters, namedFilter{name: name, filter: f})
+	return b
+}
+
+// String renders the policy in the policy language, one statement a line, to pass as
+// CreateContainerParams.PlacementPolicy
+func (b *Builder) String() string {
+	lines := make([]string, 0, len(b.replicas)+len(b.selectors)+len(b.filters)+1)
+	for _, r := range b.replicas {
+		line := "REP " + strconv.FormatUint(uint64(r.count), 10)
+		if r.selector != "" {
+			line += " IN " + r.selector
+		}
+		lines = append(lines, line)
+	}
+	if b.cbf != 0 {
+		lines = append(lines, "CBF "+strconv.FormatUint(uint64(b.cbf), 10))
+	}
+	for _, s := range b.selectors {
+		lines = append(lines, s.String())
+	}
+	for _, f := range b.filters {
+		lines = append(lines, "FILTER "+f.filter.String()+" AS "+f.name)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Build parses the rendered policy, so it fails for the policies the network would refuse
+func (b *Builder) Build() (*netmap.PlacementPolicy, error) {
+	for _, f := range b.filters {
+		if err := f.filter.check(); err != nil {
+			return nil, fmt.Errorf("invalid filter %s: %w", f.name, err)
+		}
+	}
+	p, err := policy.Parse(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid placement policy: %w", err)
+	}
+	return p, nil
+}
+
+// Validate checks the online nodes of a netmap, e.g. the current one, can store containers with policy p:
+// that every selector finds enough nodes matching its filter, in the subnet of p
+func Validate(p *netmap.PlacementPolicy, nodes []netmap.NodeInfo) error {
+	online := make([]netmap.NodeInfo, 0, len(nodes))
+	for i := range nodes {
+		if nodes[i].State() == netmap.NodeStateOnline {
+			online = append(online, nodes[i])
+		}
+	}
+	nm, err := netmap.NewNetmap(netmap.NodesFromInfo(online))
+	if err != nil {
+		return err
+	}
+	if _, err := nm.GetContainerNodes(p, nil); err != nil {
+		if errors.Is(err, netmap.ErrNotEnoughNodes) {
+			if reason := shortage(p, nm.Nodes); reason != "" {
+				return fmt.Errorf("placement policy can't be satisfied: %w, %s", err, reason)
+			}
+		}
+		return fmt.Errorf("placement policy can't be satisfied: %w", err)
+	}
+	return nil
+}
+
+// shortage describes the first selector of p that nodes can't satisfy. Like the netmap, it only counts nodes in the
+// subnet of p, the zero subnet if p names none
+func shortage(p *netmap.PlacementPolicy, nodes netmap.Nodes) string {
+	var subnet subnetid.ID
+	if p.SubnetID() != nil {
+		subnet = *p.SubnetID()
+	}
+	filters := make(map[string]*netmap.Filter, len(p.Filters()))
+	for _, f := range p.Filters() {
+		filters[f.Name()] = f
+	}
+	selectors := p.Selectors()
+	if len(selectors) == 0 {
+		// REP n without selectors selects n nodes from the whole netmap
+		for _, r := range p.Replicas() {
+			s := netmap.NewSelector()
+			s.SetCount(r.Count())
+			s.SetFilter(netmap.MainFilterName)
+			selectors = append(selectors, s)
+		}
+	}
+	for _, s := range selectors {
+		f := filters[s.Filter()]
+		buckets := make(map[string]int)
+		matching := 0
+		for _, n := range nodes {
+			if !netmap.BelongsToSubnet(n.NodeInfo, subnet) {
+				continue
+			}
+			if s.Filter() == netmap.MainFilterName || match(filters, f, n) {
+				matching++
+				buckets[n.Attribute(s.Attribute())]++
+			}
+		}
+		name := s.Name()
+		if name == "" {
+			name = "REP"
+		}
+		switch {
+		case s.Attribute() == "" && matching < int(s.Count()):
+			return fmt.Sprintf("%s needs %d nodes from %s, %d online nodes match", name, s.Count(), s.Filter(), matching)
+		case s.Attribute() != "" && s.Clause() == CLAUSE_SAME:
+			largest := 0
+			for _, n := range buckets {
+				if n > largest {
+					largest = n
+				}
+			}
+			if largest < int(s.Count()) {
+				return fmt.Sprintf("%s needs %d nodes with the same %s from %s, at most %d online nodes match",
+					name, s.Count(), s.Attribute(), s.Filter(), largest)
+			}
+		case s.Attribute() != "" && len(buckets) < int(s.Count()):
+			return fmt.Sprintf("%s needs %d distinct %s values from %s, %d online nodes match with %d values",
+				name, s.Count(), s.Attribute(), s.Filter(), matching, len(buckets))
+		}
+	}
+	return ""
+}
+
+// match mirrors the filtering of netmap.Netmap, which is not exported
+func match(filters map[string]*netmap.Filter, f *netmap.Filter, n *netmap.Node) bool {
+	if f == nil {
+		return false
+	}
+	switch f.Operation() {
+	case netmap.OpAND, netmap.OpOR:
+		for _, in := range f.InnerFilters() {
+			if in.Name() != "" {
+				in = filters[in.Name()]
+			}
+			if ok := match(filters, in, n); ok == (f.Operation() == netmap.OpOR) {
+				return ok
+			}
+		}
+		return f.Operation() == netmap.OpAND
+	case netmap.OpEQ:
+		return n.Attribute(f.Key()) == f.Value()
+	case netmap.OpNE:
+		return n.Attribute(f.Key()) != f.Value()
+	}
+	attr, err := strconv.ParseUint(n.Attribute(f.Key()), 10, 64)
+	if err != nil {
+		return false
+	}
+	value, err := strconv.ParseUint(f.Value(), 10, 64)
+	if err != nil {
+		return false
+	}
+	switch f.Operation() {
+	case netmap.OpGT:
+		return attr > value
+	case netmap.OpGE:
+		return attr >= value
+	case netmap.OpLT:
+		return attr < value
+	case netmap.OpLE:
+		return attr <= value
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package policy_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/configwizard/gaspump-api/pkg/policy"
+	"github.com/nspcc-dev/neofs-sdk-go/netmap"
+	subnetid "github.com/nspcc-dev/neofs-sdk-go/subnet/id"
+	"github.com/stretchr/testify/assert"
+)
+
+func node(key byte, state netmap.NodeState, attrs ...string) netmap.NodeInfo {
+	var n netmap.NodeInfo
+	n.SetPublicKey([]byte{2, key})
+	n.SetState(state)
+	as := make([]*netmap.NodeAttribute, 0, len(attrs)/2)
+	for i := 0; i < len(attrs); i += 2 {
+		a := netmap.NewNodeAttribute()
+		a.SetKey(attrs[i])
+		a.SetValue(attrs[i+1])
+		as = append(as, a)
+	}
+	n.SetAttributes(as...)
+	return n
+}
+
+func euPolicy() *policy.Builder {
+	return policy.NewBuilder().
+		Replicas(2, "EU").
+		BackupFactor(1).
+		Select(policy.Selector{Name: "EU", Count: 2, Clause: policy.CLAUSE_DISTINCT, Attribute: netmap.AttrCountry, Filter: "CHEAP_EU"}).
+		Filter("EU_OR_NA", policy.Or(policy.Continent("Europe"), policy.Continent("North America"))).
+		Filter("CHEAP_EU", policy.And(policy.Ref("EU_OR_NA"), policy.MaxPrice(10), policy.Ne(netmap.AttrCountry, "AND")))
+}
+
+func TestBuilder(t *testing.T) {
+	b := euPolicy()
+	assert.Equal(t, strings.Join([]string{
+		"REP 2 IN EU",
+		"CBF 1",
+		"SELECT 2 IN DISTINCT Country FROM CHEAP_EU AS EU",
+		`FILTER Continent EQ Europe OR Continent EQ "North America" AS EU_OR_NA`,
+		`FILTER @EU_OR_NA AND Price LE 10 AND Country NE "AND" AS CHEAP_EU`,
+	}, "\n"), b.String())
+
+	p, err := b.Build()
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, uint32(1), p.ContainerBackupFactor())
+	assert.Len(t, p.Filters(), 2)
+	assert.Equal(t, "North America", p.Filters()[0].InnerFilters()[1].Value())
+
+	nested := policy.NewBuilder().Replicas(1, "").
+		Filter("F", policy.And(policy.Or(policy.Country("Germany"), policy.CountryCode("FR")), policy.MinCapacity(100)))
+	assert.Contains(t, nested.String(), "FILTER (Country EQ Germany OR CountryCode EQ FR) AND Capacity GE 100 AS F")
+	_, err = nested.Build()
+	assert.Nil(t, err, "error not nil")
+
+	_, err = policy.NewBuilder().Replicas(1, "MISSING").Build()
+	assert.NotNil(t, err, "unknown selector accepted")
+	_, err = policy.NewBuilder().Replicas(0, "").Build()
+	assert.NotNil(t, err, "zero replicas accepted")
+}
+
+func TestValidate(t *testing.T) {
+	p, err := euPolicy().Build()
+	assert.Nil(t, err, "error not nil")
+
+	nodes := []netmap.NodeInfo{
+		node(1, netmap.NodeStateOnline, netmap.AttrContinent, "Europe", netmap.AttrCountry, "Germany", netmap.AttrPrice, "5"),
+		node(2, netmap.NodeStateOnline, netmap.AttrContinent, "Europe", netmap.AttrCountry, "Germany", netmap.AttrPrice, "8"),
+		node(3, netmap.NodeStateOnline, netmap.AttrContinent, "Europe", netmap.AttrCountry, "France", netmap.AttrPrice, "50"),
+		node(4, netmap.NodeStateOffline, netmap.AttrContinent, "Europe", netmap.AttrCountry, "Spain", netmap.AttrPrice, "1"),
+		node(5, netmap.NodeStateOnline, netmap.AttrContinent, "Asia", netmap.AttrCountry, "Japan", netmap.AttrPrice, "1"),
+	}
+	err = policy.Validate(p, nodes)
+	assert.True(t, errors.Is(err, netmap.ErrNotEnoughNodes), "policy satisfied by one country")
+	assert.Contains(t, err.Error(), "EU needs 2 distinct Country values from CHEAP_EU, 2 online nodes match with 1 values")
+
+	nodes = append(nodes, node(6, netmap.NodeStateOnline, netmap.AttrContinent, "North America", netmap.AttrCountry, "Canada", netmap.AttrPrice, "10"))
+	assert.Nil(t, policy.Validate(p, nodes), "error not nil")
+
+	// neither a node in no state nor one that left the zero subnet counts
+	unspecified := node(7, 0, netmap.AttrContinent, "Europe", netmap.AttrCountry, "Spain", netmap.AttrPrice, "1")
+	outside := node(8, netmap.NodeStateOnline, netmap.AttrContinent, "Europe", netmap.AttrCountry, "Italy", netmap.AttrPrice, "1")
+	outside.ExitSubnet(subnetid.ID{})
+	nodes = append(nodes, unspecified, outside)
+
+	rep, err := policy.NewBuilder().Replicas(6, "").Build()
+	assert.Nil(t, err, "error not nil")
+	err = policy.Validate(rep, nodes)
+	assert.True(t, errors.Is(err, netmap.ErrNotEnoughNodes), "policy satisfied by too few nodes")
+	assert.Contains(t, err.Error(), "REP needs 6 nodes from *, 5 online nodes match")
+}
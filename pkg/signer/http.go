@@ -0,0 +1,110 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	SignPath    = "/sign"
+	PendingPath = "/pending"
+	RespondPath = "/respond"
+
+	// maxMessageSize bounds request bodies; transactions and tokens are far smaller
+	maxMessageSize = 1 << 20
+)
+
+// Client sends requests to a signer served by NewServer
+type Client struct {
+	endpoint string
+	http     *http.Client
+}
+
+// NewClient talks to the signer at endpoint, e.g. http://localhost:8090. httpClient may be nil
+func NewClient(endpoint string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{endpoint: strings.TrimSuffix(endpoint, "/"), http: httpClient}
+}
+
+// Sign sends req and returns the response once it has been checked against the request
+func (c *Client) Sign(ctx context.Context, req *Request) (*Response, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+SignPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+	resp := &Response{}
+	if err := json.NewDecoder(io.LimitReader(httpResp.Body, maxMessageSize)).Decode(resp); err != nil {
+		return nil, fmt.Errorf("remote signer returned %s: %w", httpResp.Status, err)
+	}
+	if httpResp.StatusCode != http.StatusOK && resp.Error == "" {
+		return nil, fmt.Errorf("remote signer returned %s", httpResp.Status)
+	}
+	if err := VerifyResponse(req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// NewServer exposes h over HTTP. Requests are POSTed as JSON to SignPath
+func NewServer(h Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(SignPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		req := &Request{}
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxMessageSize)).Decode(req); err != nil {
+			writeResponse(w, http.StatusBadRequest, &Response{Error: err.Error()})
+			return
+		}
+		if err := req.Validate(); err != nil {
+			writeResponse(w, http.StatusBadRequest, &Response{ID: req.ID, Error: err.Error()})
+			return
+		}
+		resp, err := h.Sign(r.Context(), req)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, context.DeadlineExceeded) {
+				status = http.StatusGatewayTimeout
+			} else if errors.Is(err, ErrDuplicateRequest) {
+				status = http.StatusConflict
+			}
+			writeResponse(w, status, &Response{ID: req.ID, Error: err.Error()})
+			return
+		}
+		if resp.Error != "" {
+			writeResponse(w, http.StatusForbidden, resp)
+			return
+		}
+		writeResponse(w, http.StatusOK, resp)
+	})
+	return mux
+}
+
+func writeResponse(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,51 @@
+package signer
+
+import (
+	"context"
+	"encoding/hex"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+)
+
+// LocalSigner answers requests with a key held in memory. It is meant for tests and for
+// running the signing side of the protocol next to a wallet the process already has open
+type LocalSigner struct {
+	key     *keys.PrivateKey
+	approve func(req *Request) bool
+}
+
+// NewLocalSigner signs every request addressed to key. approve may be nil to accept everything
+func NewLocalSigner(key *keys.PrivateKey, approve func(req *Request) bool) *LocalSigner {
+	return &LocalSigner{key: key, approve: approve}
+}
+
+// PublicKey is the key requests should be hinted with
+func (l *LocalSigner) PublicKey() *keys.PublicKey {
+	return l.key.PublicKey()
+}
+
+func (l *LocalSigner) Sign(ctx context.Context, req *Request) (*Response, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	resp := &Response{ID: req.ID}
+	if req.KeyHint != "" && req.KeyHint != hex.EncodeToString(l.key.PublicKey().Bytes()) {
+		resp.Error = ErrUnknownKey.Error()
+		return resp, nil
+	}
+	if l.approve != nil && !l.approve(req) {
+		resp.Error = ErrDeclined.Error()
+		return resp, nil
+	}
+	sig, err := Sign(l.key, req.Scheme, req.Payload)
+	if err != nil {
+		return nil, err
+	}
+	resp.PublicKey = l.key.PublicKey().Bytes()
+	resp.Signature = sig
+	resp.Scheme = req.Scheme
+	return resp, nil
+}
@@ -0,0 +1,209 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neofs-api-go/v2/refs"
+)
+
+// Scheme names the signature algorithm the signer must use
+type Scheme string
+
+const (
+	// SchemeSHA512 is the NeoFS default, ECDSA over SHA-512 with the signature encoded as an uncompressed point
+	SchemeSHA512 Scheme = "ECDSA_SHA512"
+	// SchemeRFC6979 is deterministic ECDSA over SHA-256, as used by neo-go for transactions
+	SchemeRFC6979 Scheme = "ECDSA_RFC6979_SHA256"
+)
+
+// PayloadType tells the signer what it is being asked to sign so it can show the user something sensible
+type PayloadType string
+
+const (
+	PayloadBearerToken  PayloadType = "bearer-token"
+	PayloadSessionToken PayloadType = "session-token"
+	PayloadTransaction  PayloadType = "transaction"
+	PayloadRaw          PayloadType = "raw"
+)
+
+var (
+	ErrUnknownKey       = errors.New("signer does not hold the requested key")
+	ErrUnknownScheme    = errors.New("unsupported signature scheme")
+	ErrInvalidSignature = errors.New("signature does not match payload")
+	ErrDeclined         = errors.New("signing request declined")
+	ErrUnknownRequest   = errors.New("no pending request with that id")
+)
+
+// Request is sent to a remote signer. Payload is the exact bytes to sign (e.g. a token body's StableMarshal),
+// KeyHint is the hex encoded compressed public key expected to sign it, or empty if any key will do
+type Request struct {
+	ID          string      `json:"id"`
+	Type        PayloadType `json:"type"`
+	Scheme      Scheme      `json:"scheme"`
+	KeyHint     string      `json:"keyHint,omitempty"`
+	Payload     []byte      `json:"payload"`
+	Description string      `json:"description,omitempty"`
+}
+
+// Response is returned by a remote signer. Error is set instead of Signature if the request was refused
+type Response struct {
+	ID        string `json:"id"`
+	PublicKey []byte `json:"publicKey,omitempty"`
+	Signature []byte `json:"signature,omitempty"`
+	Scheme    Scheme `json:"scheme,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Handler signs requests. LocalSigner, Client and Relay all implement it
+type Handler interface {
+	Sign(ctx context.Context, req *Request) (*Response, error)
+}
+
+// NewRequest builds a request with a random ID. keyHint may be nil
+func NewRequest(payloadType PayloadType, scheme Scheme, payload []byte, keyHint *keys.PublicKey) (*Request, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	req := &Request{
+		ID:      hex.EncodeToString(id),
+		Type:    payloadType,
+		Scheme:  scheme,
+		Payload: payload,
+	}
+	if keyHint != nil {
+		req.KeyHint = hex.EncodeToString(keyHint.Bytes())
+	}
+	return req, nil
+}
+
+// Validate checks a request is complete before it is sent or handled
+func (r *Request) Validate() error {
+	if r.ID == "" {
+		return errors.New("request has no id")
+	}
+	if len(r.Payload) == 0 {
+		return errors.New("request has no payload")
+	}
+	if _, err := r.Scheme.ToV2(); err != nil {
+		return err
+	}
+	if r.KeyHint != "" {
+		if _, err := keys.NewPublicKeyFromString(r.KeyHint); err != nil {
+			return fmt.Errorf("invalid key hint: %w", err)
+		}
+	}
+	return nil
+}
+
+// ToV2 maps the scheme onto the NeoFS API enum
+func (s Scheme) ToV2() (refs.SignatureScheme, error) {
+	switch s {
+	case SchemeSHA512:
+		return refs.ECDSA_SHA512, nil
+	case SchemeRFC6979:
+		return refs.ECDSA_RFC6979_SHA256, nil
+	case SchemeWalletConnect:
+		return ECDSA_RFC6979_SHA256_WALLET_CONNECT, nil
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnknownScheme, s)
+}
+
+// RefsSignature converts a successful response into the structure NeoFS tokens carry
+func (r *Response) RefsSignature() (*refs.Signature, error) {
+	return NewRefsSignature(r.PublicKey, r.Signature, r.Scheme)
+}
+
+// Sign signs payload with key using the given scheme
+func Sign(key *keys.PrivateKey, scheme Scheme, payload []byte) ([]byte, error) {
+	switch scheme {
+	case SchemeSHA512:
+		h := sha512.Sum512(payload)
+		r, s, err := ecdsa.Sign(rand.Reader, &key.PrivateKey, h[:])
+		if err != nil {
+			return nil, err
+		}
+		return marshalXY(r, s), nil
+	case SchemeRFC6979:
+		return key.Sign(payload), nil
+	case SchemeWalletConnect:
+		return signWalletConnect(key, payload)
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownScheme, scheme)
+}
+
+// Verify checks sig was made over payload by the holder of publicKey (compressed or uncompressed)
+func Verify(publicKey []byte, scheme Scheme, payload, sig []byte) error {
+	pub, err := keys.NewPublicKeyFromBytes(publicKey, elliptic.P256())
+	if err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	switch scheme {
+	case SchemeSHA512:
+		h := sha512.Sum512(payload)
+		r, s := unmarshalXY(sig)
+		if r != nil && ecdsa.Verify((*ecdsa.PublicKey)(pub), h[:], r, s) {
+			return nil
+		}
+		return ErrInvalidSignature
+	case SchemeRFC6979:
+		h := sha256.Sum256(payload)
+		if pub.Verify(sig, h[:]) {
+			return nil
+		}
+		return ErrInvalidSignature
+	case SchemeWalletConnect:
+		return verifyWalletConnect(pub, payload, sig)
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownScheme, scheme)
+}
+
+// VerifyResponse checks a response answers req, was made by the hinted key and is a valid signature
+func VerifyResponse(req *Request, resp *Response) error {
+	if resp.ID != req.ID {
+		return fmt.Errorf("response %s does not match request %s", resp.ID, req.ID)
+	}
+	if resp.Error != "" {
+		return fmt.Errorf("%w: %s", ErrDeclined, resp.Error)
+	}
+	if resp.Scheme != req.Scheme {
+		return fmt.Errorf("signed with %s, requested %s", resp.Scheme, req.Scheme)
+	}
+	if req.KeyHint != "" {
+		pub, err := keys.NewPublicKeyFromBytes(resp.PublicKey, elliptic.P256())
+		if err != nil {
+			return fmt.Errorf("invalid public key: %w", err)
+		}
+		if hex.EncodeToString(pub.Bytes()) != req.KeyHint {
+			return ErrUnknownKey
+		}
+	}
+	return Verify(resp.PublicKey, resp.Scheme, req.Payload, resp.Signature)
+}
+
+// marshalXY encodes r and s the way NeoFS expects, in the layout of an uncompressed point.
+// elliptic.Marshal can't be used as r and s are not a point on the curve
+func marshalXY(r, s *big.Int) []byte {
+	buf := make([]byte, 65)
+	buf[0] = 4
+	r.FillBytes(buf[1:33])
+	s.FillBytes(buf[33:])
+	return buf
+}
+
+func unmarshalXY(data []byte) (*big.Int, *big.Int) {
+	if len(data) != 65 || data[0] != 4 {
+		return nil, nil
+	}
+	return new(big.Int).SetBytes(data[1:33]), new(big.Int).SetBytes(data[33:])
+}
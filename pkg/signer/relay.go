@@ -0,0 +1,193 @@
+package signer
+
+import (
+	"context"
+	"crypto/elliptic"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+)
+
+var (
+	ErrDuplicateRequest = errors.New("a request with that id is already pending")
+	ErrUnauthenticated  = errors.New("wallet is not authenticated")
+	ErrNotRouted        = errors.New("request is not for this wallet")
+)
+
+// Authenticator tells which wallet sent an HTTP request to the relay, by the key it signs with,
+// or fails if the request carries no valid credentials
+type Authenticator func(req *http.Request) (*keys.PublicKey, error)
+
+// TokenAuthenticator authenticates wallets by a secret sent as "Authorization: Bearer <token>",
+// tokens maps each wallet's secret to its key
+func TokenAuthenticator(tokens map[string]*keys.PublicKey) Authenticator {
+	return func(req *http.Request) (*keys.PublicKey, error) {
+		presented := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if presented == "" {
+			return nil, ErrUnauthenticated
+		}
+		for token, key := range tokens {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(presented)) == 1 {
+				return key, nil
+			}
+		}
+		return nil, ErrUnauthenticated
+	}
+}
+
+// Relay lets a signer that can't accept connections, such as a browser wallet, answer requests.
+// The gateway calls Sign, which blocks; the wallet polls PendingPath and posts its answers to RespondPath.
+// Wallets are authenticated on both paths, they only see and answer the requests for their key or for any key
+type Relay struct {
+	mu      sync.Mutex
+	pending map[string]*pendingRequest
+	order   []string
+	auth    Authenticator
+}
+
+type pendingRequest struct {
+	req  *Request
+	done chan *Response
+}
+
+// NewRelay serves wallets that auth accepts, e.g. a TokenAuthenticator. With a nil auth every HTTP request is
+// refused and the relay can only be answered in process
+func NewRelay(auth Authenticator) *Relay {
+	return &Relay{pending: make(map[string]*pendingRequest), auth: auth}
+}
+
+// Sign queues req and waits for a wallet to answer it or ctx to finish
+func (r *Relay) Sign(ctx context.Context, req *Request) (*Response, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	p := &pendingRequest{req: req, done: make(chan *Response, 1)}
+	r.mu.Lock()
+	if _, ok := r.pending[req.ID]; ok {
+		r.mu.Unlock()
+		return nil, ErrDuplicateRequest
+	}
+	r.pending[req.ID] = p
+	r.order = append(r.order, req.ID)
+	r.mu.Unlock()
+	defer r.remove(req.ID)
+
+	select {
+	case resp := <-p.done:
+		return resp, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Pending lists waiting requests, oldest first. If keyHint is not empty only requests for that key,
+// or for any key, are returned
+func (r *Relay) Pending(keyHint string) []*Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var requests []*Request
+	for _, id := range r.order {
+		req := r.pending[id].req
+		if keyHint == "" || req.KeyHint == "" || req.KeyHint == keyHint {
+			requests = append(requests, req)
+		}
+	}
+	return requests
+}
+
+// Respond delivers the answer of the wallet holding key to the waiting caller. The request must be for that key
+// or any key, signed responses are verified first
+func (r *Relay) Respond(key *keys.PublicKey, resp *Response) error {
+	r.mu.Lock()
+	p, ok := r.pending[resp.ID]
+	r.mu.Unlock()
+	if !ok {
+		return ErrUnknownRequest
+	}
+	walletKey := hex.EncodeToString(key.Bytes())
+	if p.req.KeyHint != "" && p.req.KeyHint != walletKey {
+		return ErrNotRouted
+	}
+	if resp.Error == "" {
+		pub, err := keys.NewPublicKeyFromBytes(resp.PublicKey, elliptic.P256())
+		if err != nil || !pub.Equal(key) {
+			return ErrNotRouted
+		}
+		if err := VerifyResponse(p.req, resp); err != nil {
+			return err
+		}
+	}
+	select {
+	case p.done <- resp:
+	default:
+		// already answered
+	}
+	return nil
+}
+
+func (r *Relay) remove(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.pending, id)
+	for i, o := range r.order {
+		if o == id {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// ServeHTTP serves PendingPath (GET) and RespondPath (POST a Response) to authenticated wallets
+func (r *Relay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.auth == nil {
+		writeResponse(w, http.StatusUnauthorized, &Response{Error: ErrUnauthenticated.Error()})
+		return
+	}
+	key, err := r.auth(req)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, &Response{Error: err.Error()})
+		return
+	}
+	switch req.URL.Path {
+	case PendingPath:
+		if req.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		pending := r.Pending(hex.EncodeToString(key.Bytes()))
+		if pending == nil {
+			pending = []*Request{}
+		}
+		writeResponse(w, http.StatusOK, pending)
+	case RespondPath:
+		if req.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		resp := &Response{}
+		if err := json.NewDecoder(io.LimitReader(req.Body, maxMessageSize)).Decode(resp); err != nil {
+			writeResponse(w, http.StatusBadRequest, &Response{Error: err.Error()})
+			return
+		}
+		if err := r.Respond(key, resp); err == ErrUnknownRequest {
+			writeResponse(w, http.StatusNotFound, &Response{ID: resp.ID, Error: err.Error()})
+			return
+		} else if err == ErrNotRouted {
+			writeResponse(w, http.StatusForbidden, &Response{ID: resp.ID, Error: err.Error()})
+			return
+		} else if err != nil {
+			writeResponse(w, http.StatusBadRequest, &Response{ID: resp.ID, Error: err.Error()})
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
@@ -0,0 +1,121 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+)
+
+// Signer signs on behalf of a key that the caller may never see
+type Signer interface {
+	PublicKey() *keys.PublicKey
+	Sign(ctx context.Context, scheme Scheme, payload []byte) ([]byte, error)
+}
+
+// PreferredScheme is implemented by signers that can only, or would rather, sign with one scheme
+type PreferredScheme interface {
+	Scheme() Scheme
+}
+
+// DefaultScheme is the scheme tokens should be signed with by s
+func DefaultScheme(s Signer) Scheme {
+	if p, ok := s.(PreferredScheme); ok {
+		return p.Scheme()
+	}
+	return SchemeSHA512
+}
+
+type payloadTypeKey struct{}
+
+// WithPayloadType labels what is being signed, so a remote signer can tell its user
+func WithPayloadType(ctx context.Context, payloadType PayloadType) context.Context {
+	return context.WithValue(ctx, payloadTypeKey{}, payloadType)
+}
+
+func payloadType(ctx context.Context) PayloadType {
+	if t, ok := ctx.Value(payloadTypeKey{}).(PayloadType); ok {
+		return t
+	}
+	return PayloadRaw
+}
+
+// KeySigner signs with a key held in memory
+type KeySigner struct {
+	key *keys.PrivateKey
+}
+
+func NewKeySigner(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{key: &keys.PrivateKey{PrivateKey: *key}}
+}
+
+func (k *KeySigner) PublicKey() *keys.PublicKey {
+	return k.key.PublicKey()
+}
+
+func (k *KeySigner) Sign(_ context.Context, scheme Scheme, payload []byte) ([]byte, error) {
+	return Sign(k.key, scheme, payload)
+}
+
+// AccountSigner signs with a neo-go wallet account that has been decrypted
+type AccountSigner struct {
+	account *wallet.Account
+}
+
+// NewAccountSigner fails if the account is still locked
+func NewAccountSigner(account *wallet.Account) (*AccountSigner, error) {
+	if account.PrivateKey() == nil {
+		return nil, errors.New("account is locked, decrypt it first")
+	}
+	return &AccountSigner{account: account}, nil
+}
+
+func (a *AccountSigner) PublicKey() *keys.PublicKey {
+	return a.account.PrivateKey().PublicKey()
+}
+
+func (a *AccountSigner) Sign(_ context.Context, scheme Scheme, payload []byte) ([]byte, error) {
+	key := a.account.PrivateKey()
+	if key == nil {
+		return nil, errors.New("account is locked")
+	}
+	return Sign(key, scheme, payload)
+}
+
+// RemoteSigner forwards to a Handler, usually a Client or Relay, and checks what comes back
+type RemoteSigner struct {
+	handler   Handler
+	publicKey *keys.PublicKey
+	scheme    Scheme
+}
+
+// NewRemoteSigner signs through h with the key publicKey. scheme is the scheme the remote wallet
+// signs tokens with, e.g. SchemeWalletConnect for a browser wallet
+func NewRemoteSigner(h Handler, publicKey *keys.PublicKey, scheme Scheme) *RemoteSigner {
+	return &RemoteSigner{handler: h, publicKey: publicKey, scheme: scheme}
+}
+
+func (r *RemoteSigner) PublicKey() *keys.PublicKey {
+	return r.publicKey
+}
+
+func (r *RemoteSigner) Scheme() Scheme {
+	return r.scheme
+}
+
+func (r *RemoteSigner) Sign(ctx context.Context, scheme Scheme, payload []byte) ([]byte, error) {
+	req, err := NewRequest(payloadType(ctx), scheme, payload, r.publicKey)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.handler.Sign(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyResponse(req, resp); err != nil {
+		return nil, err
+	}
+	return resp.Signature, nil
+}
@@ -0,0 +1,222 @@
+package signer_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/configwizard/gaspump-api/pkg/signer"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemes(t *testing.T) {
+	key, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	payload := []byte("token body")
+	for _, scheme := range []signer.Scheme{signer.SchemeSHA512, signer.SchemeRFC6979, signer.SchemeWalletConnect} {
+		sig, err := signer.Sign(key, scheme, payload)
+		assert.Nil(t, err, "error not nil")
+		assert.Nil(t, signer.Verify(key.PublicKey().Bytes(), scheme, payload, sig), "signature invalid")
+		assert.NotNil(t, signer.Verify(key.PublicKey().Bytes(), scheme, []byte("other"), sig), "signature verified over the wrong payload")
+	}
+}
+
+func TestRemoteSigner(t *testing.T) {
+	key, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	local := signer.NewLocalSigner(key, func(req *signer.Request) bool {
+		return req.Type != signer.PayloadTransaction
+	})
+	server := httptest.NewServer(signer.NewServer(local))
+	defer server.Close()
+	cli := signer.NewClient(server.URL, server.Client())
+
+	req, err := signer.NewRequest(signer.PayloadBearerToken, signer.SchemeSHA512, []byte("bearer body"), key.PublicKey())
+	assert.Nil(t, err, "error not nil")
+	resp, err := cli.Sign(context.Background(), req)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, key.PublicKey().Bytes(), resp.PublicKey)
+	sig, err := resp.RefsSignature()
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, resp.Signature, sig.GetSign())
+
+	req, err = signer.NewRequest(signer.PayloadTransaction, signer.SchemeRFC6979, []byte("tx"), nil)
+	assert.Nil(t, err, "error not nil")
+	_, err = cli.Sign(context.Background(), req)
+	assert.True(t, errors.Is(err, signer.ErrDeclined), "transaction was not declined")
+
+	other, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	req, err = signer.NewRequest(signer.PayloadRaw, signer.SchemeRFC6979, []byte("raw"), other.PublicKey())
+	assert.Nil(t, err, "error not nil")
+	_, err = cli.Sign(context.Background(), req)
+	assert.NotNil(t, err, "signed with the wrong key")
+}
+
+func TestRelay(t *testing.T) {
+	key, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	local := signer.NewLocalSigner(key, nil)
+	relay := signer.NewRelay(nil)
+
+	req, err := signer.NewRequest(signer.PayloadSessionToken, signer.SchemeSHA512, []byte("session body"), key.PublicKey())
+	assert.Nil(t, err, "error not nil")
+	go func() {
+		// the wallet side polls until the request shows up
+		for {
+			pending := relay.Pending("")
+			if len(pending) == 0 {
+				time.Sleep(time.Millisecond)
+				continue
+			}
+			forged := &signer.Response{ID: pending[0].ID, PublicKey: key.PublicKey().Bytes(), Signature: make([]byte, 65), Scheme: pending[0].Scheme}
+			assert.NotNil(t, relay.Respond(key.PublicKey(), forged), "forged signature accepted")
+			_, err := relay.Sign(context.Background(), pending[0])
+			assert.True(t, errors.Is(err, signer.ErrDuplicateRequest), "request ID reused")
+			resp, err := local.Sign(context.Background(), pending[0])
+			assert.Nil(t, err, "error not nil")
+			assert.Nil(t, relay.Respond(key.PublicKey(), resp), "error not nil")
+			return
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := relay.Sign(ctx, req)
+	assert.Nil(t, err, "error not nil")
+	assert.Nil(t, signer.VerifyResponse(req, resp), "error not nil")
+	assert.Empty(t, relay.Pending(""))
+}
+
+func TestRelayUncompressedKey(t *testing.T) {
+	key, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	other, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	relay := signer.NewRelay(nil)
+
+	req, err := signer.NewRequest(signer.PayloadRaw, signer.SchemeSHA512, []byte("payload"), key.PublicKey())
+	assert.Nil(t, err, "error not nil")
+	go func() {
+		for {
+			pending := relay.Pending("")
+			if len(pending) == 0 {
+				time.Sleep(time.Millisecond)
+				continue
+			}
+			sig, err := signer.Sign(key, pending[0].Scheme, pending[0].Payload)
+			assert.Nil(t, err, "error not nil")
+			// wallets may report their key uncompressed
+			resp := &signer.Response{ID: pending[0].ID, PublicKey: key.PublicKey().UncompressedBytes(), Signature: sig, Scheme: pending[0].Scheme}
+			assert.True(t, errors.Is(relay.Respond(other.PublicKey(), resp), signer.ErrNotRouted), "response of another wallet accepted")
+			assert.Nil(t, relay.Respond(key.PublicKey(), resp), "error not nil")
+			return
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := relay.Sign(ctx, req)
+	assert.Nil(t, err, "error not nil")
+	assert.Nil(t, signer.VerifyResponse(req, resp), "error not nil")
+}
+
+func TestRelayHTTP(t *testing.T) {
+	key, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	other, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	relay := signer.NewRelay(signer.TokenAuthenticator(map[string]*keys.PublicKey{
+		"wallet-secret": key.PublicKey(),
+		"other-secret":  other.PublicKey(),
+	}))
+	server := httptest.NewServer(relay)
+	defer server.Close()
+
+	call := func(method, path, token string, body interface{}) *http.Response {
+		var reader io.Reader
+		if body != nil {
+			data, err := json.Marshal(body)
+			assert.Nil(t, err, "error not nil")
+			reader = bytes.NewReader(data)
+		}
+		httpReq, err := http.NewRequest(method, server.URL+path, reader)
+		assert.Nil(t, err, "error not nil")
+		if token != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+token)
+		}
+		httpResp, err := server.Client().Do(httpReq)
+		assert.Nil(t, err, "error not nil")
+		return httpResp
+	}
+	pending := func(token string) []*signer.Request {
+		httpResp := call(http.MethodGet, signer.PendingPath, token, nil)
+		defer httpResp.Body.Close()
+		var requests []*signer.Request
+		assert.Nil(t, json.NewDecoder(httpResp.Body).Decode(&requests), "error not nil")
+		return requests
+	}
+
+	req, err := signer.NewRequest(signer.PayloadBearerToken, signer.SchemeSHA512, []byte("bearer body"), key.PublicKey())
+	assert.Nil(t, err, "error not nil")
+	done := make(chan error, 1)
+	go func() {
+		_, err := relay.Sign(context.Background(), req)
+		done <- err
+	}()
+	for len(relay.Pending("")) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	httpResp := call(http.MethodGet, signer.PendingPath, "", nil)
+	httpResp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, httpResp.StatusCode)
+	httpResp = call(http.MethodGet, signer.PendingPath, "guess", nil)
+	httpResp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, httpResp.StatusCode)
+	assert.Empty(t, pending("other-secret"), "request shown to another wallet")
+
+	decline := &signer.Response{ID: req.ID, Error: "no"}
+	httpResp = call(http.MethodPost, signer.RespondPath, "other-secret", decline)
+	httpResp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, httpResp.StatusCode)
+
+	requests := pending("wallet-secret")
+	assert.Len(t, requests, 1)
+	httpResp = call(http.MethodPost, signer.RespondPath, "wallet-secret", decline)
+	httpResp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, httpResp.StatusCode)
+	assert.Nil(t, <-done, "error not nil")
+}
+
+func TestSigners(t *testing.T) {
+	key, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	account := wallet.NewAccountFromPrivateKey(key)
+	accountSigner, err := signer.NewAccountSigner(account)
+	assert.Nil(t, err, "error not nil")
+	signers := []signer.Signer{
+		signer.NewKeySigner(&key.PrivateKey),
+		accountSigner,
+		signer.NewRemoteSigner(signer.NewLocalSigner(key, nil), key.PublicKey(), signer.SchemeWalletConnect),
+	}
+	payload := []byte("payload")
+	for _, s := range signers {
+		assert.Equal(t, key.PublicKey().Bytes(), s.PublicKey().Bytes())
+		scheme := signer.DefaultScheme(s)
+		sig, err := s.Sign(context.Background(), scheme, payload)
+		assert.Nil(t, err, "error not nil")
+		assert.Nil(t, signer.Verify(s.PublicKey().Bytes(), scheme, payload, sig), "signature invalid")
+	}
+
+	assert.Nil(t, account.Encrypt("pass", keys.NEP2ScryptParams()), "error not nil")
+	locked := &wallet.Account{Address: account.Address, EncryptedWIF: account.EncryptedWIF}
+	_, err = signer.NewAccountSigner(locked)
+	assert.NotNil(t, err, "locked account accepted")
+}
@@ -0,0 +1,139 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neofs-api-go/v2/refs"
+)
+
+// SchemeWalletConnect is the signMessage format of NeoLine, O3 and other WalletConnect wallets.
+// The wallet signs the base64 of the payload, prefixed with a random hex salt and wrapped as a
+// Neo signed message, with ECDSA over SHA-256. The signature is r || s || salt
+const SchemeWalletConnect Scheme = "ECDSA_RFC6979_SHA256_WALLET_CONNECT"
+
+// ECDSA_RFC6979_SHA256_WALLET_CONNECT is the NeoFS API value for SchemeWalletConnect, which the
+// vendored neofs-api-go does not define yet
+const ECDSA_RFC6979_SHA256_WALLET_CONNECT refs.SignatureScheme = 2
+
+const (
+	walletConnectSaltLen      = 16
+	walletConnectSignatureLen = 64 + walletConnectSaltLen
+)
+
+// WalletConnectMessage is the message a dApp passes to the wallet's signMessage to sign payload
+func WalletConnectMessage(payload []byte) string {
+	return base64.StdEncoding.EncodeToString(payload)
+}
+
+// WalletConnectSignature combines the hex signature and hex salt returned by a wallet's signMessage
+// into the form NeoFS carries in refs.Signature
+func WalletConnectSignature(signatureHex, saltHex string) ([]byte, error) {
+	sig, err := hex.DecodeString(signatureHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	if len(sig) != 64 {
+		return nil, fmt.Errorf("signature must be 64 bytes, got %d", len(sig))
+	}
+	salt, err := hex.DecodeString(saltHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid salt: %w", err)
+	}
+	if len(salt) != walletConnectSaltLen {
+		return nil, fmt.Errorf("salt must be %d bytes, got %d", walletConnectSaltLen, len(salt))
+	}
+	return append(sig, salt...), nil
+}
+
+// walletConnectData is the byte string a wallet actually hashes for a salted message
+func walletConnectData(salt, message []byte) []byte {
+	saltedLen := hex.EncodedLen(len(salt)) + len(message)
+	data := make([]byte, 4+io.GetVarSize(saltedLen)+saltedLen+2)
+	n := copy(data, []byte{0x01, 0x00, 0x01, 0xf0})
+	n += io.PutVarUint(data[n:], uint64(saltedLen))
+	n += hex.Encode(data[n:], salt)
+	n += copy(data[n:], message)
+	copy(data[n:], []byte{0x00, 0x00})
+	return data
+}
+
+func signWalletConnect(key *keys.PrivateKey, payload []byte) ([]byte, error) {
+	salt := make([]byte, walletConnectSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(walletConnectData(salt, []byte(WalletConnectMessage(payload))))
+	r, s, err := ecdsa.Sign(rand.Reader, &key.PrivateKey, h[:])
+	if err != nil {
+		return nil, err
+	}
+	sig := make([]byte, walletConnectSignatureLen)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:64])
+	copy(sig[64:], salt)
+	return sig, nil
+}
+
+func verifyWalletConnect(pub *keys.PublicKey, payload, sig []byte) error {
+	if len(sig) != walletConnectSignatureLen {
+		return ErrInvalidSignature
+	}
+	h := sha256.Sum256(walletConnectData(sig[64:], []byte(WalletConnectMessage(payload))))
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	if ecdsa.Verify((*ecdsa.PublicKey)(pub), h[:], r, s) {
+		return nil
+	}
+	return ErrInvalidSignature
+}
+
+// SchemeFromV2 maps a NeoFS API scheme back onto a Scheme
+func SchemeFromV2(scheme refs.SignatureScheme) (Scheme, error) {
+	switch scheme {
+	case refs.ECDSA_SHA512:
+		return SchemeSHA512, nil
+	case refs.ECDSA_RFC6979_SHA256:
+		return SchemeRFC6979, nil
+	case ECDSA_RFC6979_SHA256_WALLET_CONNECT:
+		return SchemeWalletConnect, nil
+	}
+	return "", fmt.Errorf("%w: %d", ErrUnknownScheme, scheme)
+}
+
+// NewRefsSignature builds the signature structure a token carries, with the scheme set
+func NewRefsSignature(publicKey, sig []byte, scheme Scheme) (*refs.Signature, error) {
+	v2, err := scheme.ToV2()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := keys.NewPublicKeyFromBytes(publicKey, elliptic.P256()); err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	signature := new(refs.Signature)
+	signature.SetKey(publicKey)
+	signature.SetSign(sig)
+	signature.SetScheme(v2)
+	return signature, nil
+}
+
+// VerifyRefsSignature checks a token signature over payload using whichever scheme it declares
+func VerifyRefsSignature(signature *refs.Signature, payload []byte) error {
+	if signature == nil {
+		return errors.New("missing signature")
+	}
+	scheme, err := SchemeFromV2(signature.GetScheme())
+	if err != nil {
+		return err
+	}
+	return Verify(signature.GetKey(), scheme, payload, signature.GetSign())
+}
@@ -0,0 +1,101 @@
+package tokens
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// Record is the audit entry kept for every token an Issuer hands out.
+// Receiver is the holder's hex encoded public key, kept so it can be excluded from a container eACL on revocation
+type Record struct {
+	ID        string    `json:"id"`
+	Template  Template  `json:"template"`
+	Container string    `json:"container"`
+	Object    string    `json:"object,omitempty"`
+	Owner     string    `json:"owner"`
+	Receiver  string    `json:"receiver"`
+	Issuer    string    `json:"issuer,omitempty"`
+	IssuedAt  uint64    `json:"issuedAt"`
+	Expiry    uint64    `json:"expiry"`
+	Signed    bool      `json:"signed"`
+	Created   time.Time `json:"created"`
+}
+
+// AuditLog stores records of issued tokens
+type AuditLog interface {
+	Record(r Record) error
+	Records() ([]Record, error)
+}
+
+// MemoryAuditLog keeps records for the lifetime of the process
+type MemoryAuditLog struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+func (m *MemoryAuditLog) Record(r Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = append(m.records, r)
+	return nil
+}
+
+func (m *MemoryAuditLog) Records() ([]Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	records := make([]Record, len(m.records))
+	copy(records, m.records)
+	return records, nil
+}
+
+// FileAuditLog appends records to a file, one JSON document per line
+type FileAuditLog struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileAuditLog(path string) *FileAuditLog {
+	return &FileAuditLog{path: path}
+}
+
+func (f *FileAuditLog) Record(r Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+func (f *FileAuditLog) Records() ([]Record, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, err := os.Open(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return records, err
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
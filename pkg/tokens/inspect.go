@@ -0,0 +1,290 @@
+package tokens
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	client2 "github.com/configwizard/gaspump-api/pkg/client"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neofs-sdk-go/client"
+	"github.com/nspcc-dev/neofs-sdk-go/eacl"
+	"github.com/nspcc-dev/neofs-sdk-go/owner"
+	"github.com/nspcc-dev/neofs-sdk-go/session"
+	"github.com/nspcc-dev/neofs-sdk-go/token"
+)
+
+// Verdict is a human readable outcome of a single check made while inspecting a token
+type Verdict struct {
+	OK      bool   `json:"ok"`
+	Message string `json:"message"`
+}
+
+// Inspection describes a bearer or session token
+type Inspection struct {
+	Kind           string    `json:"kind"`
+	Issuer         string    `json:"issuer"`
+	Owner          string    `json:"owner"`
+	SignerKey      string    `json:"signerKey,omitempty"`
+	SignatureValid bool      `json:"signatureValid"`
+	IssuedAt       uint64    `json:"issuedAt"`
+	NotBefore      uint64    `json:"notBefore"`
+	Expiry         uint64    `json:"expiry"`
+	CurrentEpoch   uint64    `json:"currentEpoch"`
+	Records        []string  `json:"records,omitempty"`
+	Context        []string  `json:"context,omitempty"`
+	Verdicts       []Verdict `json:"verdicts"`
+}
+
+// Valid is true when every check passed
+func (i Inspection) Valid() bool {
+	for _, v := range i.Verdicts {
+		if !v.OK {
+			return false
+		}
+	}
+	return true
+}
+
+func (i Inspection) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s token\n", i.Kind)
+	fmt.Fprintf(&b, "  issuer:    %s\n", orNone(i.Issuer))
+	fmt.Fprintf(&b, "  owner:     %s\n", orNone(i.Owner))
+	if i.SignerKey != "" {
+		fmt.Fprintf(&b, "  key:       %s\n", i.SignerKey)
+	}
+	fmt.Fprintf(&b, "  lifetime:  iat %d, nbf %d, exp %d (current epoch %d)\n", i.IssuedAt, i.NotBefore, i.Expiry, i.CurrentEpoch)
+	if len(i.Records) > 0 {
+		b.WriteString("  eACL records:\n")
+		for _, r := range i.Records {
+			fmt.Fprintf(&b, "    %s\n", r)
+		}
+	}
+	if len(i.Context) > 0 {
+		b.WriteString("  session context:\n")
+		for _, c := range i.Context {
+			fmt.Fprintf(&b, "    %s\n", c)
+		}
+	}
+	b.WriteString("  verdicts:\n")
+	for _, v := range i.Verdicts {
+		mark := "FAIL"
+		if v.OK {
+			mark = "ok"
+		}
+		fmt.Fprintf(&b, "    [%s] %s\n", mark, v.Message)
+	}
+	return b.String()
+}
+
+// DecodeBearerToken accepts a bearer token as protobuf binary, JSON or base64 encoded binary
+func DecodeBearerToken(data []byte) (*token.BearerToken, error) {
+	bt := token.NewBearerToken()
+	err := decode(data, bt.UnmarshalJSON, bt.Unmarshal)
+	return bt, err
+}
+
+// DecodeSessionToken accepts a session token as protobuf binary, JSON or base64 encoded binary
+func DecodeSessionToken(data []byte) (*session.Token, error) {
+	st := session.NewToken()
+	err := decode(data, st.UnmarshalJSON, st.Unmarshal)
+	return st, err
+}
+
+func decode(data []byte, fromJSON, fromBinary func([]byte) error) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return errors.New("empty token")
+	}
+	if trimmed[0] == '{' {
+		return fromJSON(trimmed)
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if raw, err := enc.DecodeString(string(trimmed)); err == nil {
+			if err := fromBinary(raw); err == nil {
+				return nil
+			}
+		}
+	}
+	if err := fromBinary(data); err != nil {
+		return fmt.Errorf("token is not valid JSON, base64 or binary: %w", err)
+	}
+	return nil
+}
+
+// InspectBearerToken checks the signature and lifetime of a bearer token against currentEpoch
+func InspectBearerToken(bt *token.BearerToken, currentEpoch uint64) Inspection {
+	i := Inspection{
+		Kind:         "bearer",
+		Owner:        idString(bt.OwnerID()),
+		IssuedAt:     bt.IssuedAt(),
+		NotBefore:    bt.NotBeforeTime(),
+		Expiry:       bt.Expiration(),
+		CurrentEpoch: currentEpoch,
+	}
+	sig := bt.Signature()
+	if sig == nil || len(sig.Key()) == 0 {
+		i.Verdicts = append(i.Verdicts, Verdict{false, "token is not signed"})
+	} else {
+		i.Issuer = idString(bt.Issuer())
+		i.SignerKey = hex.EncodeToString(sig.Key())
+		if err := client2.VerifyBearerTokenSignature(bt); err != nil {
+			i.Verdicts = append(i.Verdicts, Verdict{false, "signature is invalid: " + err.Error()})
+		} else {
+			i.SignatureValid = true
+			i.Verdicts = append(i.Verdicts, Verdict{true, fmt.Sprintf("signature is valid (%s)", sig.Scheme())})
+		}
+	}
+	i.Verdicts = append(i.Verdicts, lifetimeVerdicts(i.NotBefore, i.Expiry, currentEpoch)...)
+	if table := bt.EACLTable(); table != nil {
+		for _, r := range table.Records() {
+			i.Records = append(i.Records, DescribeRecord(r))
+		}
+		if len(i.Records) == 0 {
+			i.Verdicts = append(i.Verdicts, Verdict{false, "eACL table has no records"})
+		}
+	}
+	return i
+}
+
+// InspectSessionToken checks the signature and lifetime of a session token against currentEpoch
+func InspectSessionToken(st *session.Token, currentEpoch uint64) Inspection {
+	i := Inspection{
+		Kind:         "session",
+		Owner:        idString(st.OwnerID()),
+		IssuedAt:     st.Iat(),
+		NotBefore:    st.Nbf(),
+		Expiry:       st.Exp(),
+		CurrentEpoch: currentEpoch,
+	}
+	sig := st.Signature()
+	if sig == nil || len(sig.Key()) == 0 {
+		i.Verdicts = append(i.Verdicts, Verdict{false, "token is not signed"})
+	} else {
+		i.SignerKey = hex.EncodeToString(sig.Key())
+		if pub, err := keys.NewPublicKeyFromBytes(sig.Key(), elliptic.P256()); err == nil {
+			i.Issuer = owner.NewIDFromPublicKey((*ecdsa.PublicKey)(pub)).String()
+		}
+		if err := client2.VerifySessionTokenSignature(st); err != nil {
+			i.Verdicts = append(i.Verdicts, Verdict{false, "signature is invalid: " + err.Error()})
+		} else {
+			i.SignatureValid = true
+			i.Verdicts = append(i.Verdicts, Verdict{true, fmt.Sprintf("signature is valid (%s)", sig.Scheme())})
+		}
+		if i.Issuer != "" && i.Issuer != i.Owner {
+			i.Verdicts = append(i.Verdicts, Verdict{false, "token was signed by " + i.Issuer + " which is not the owner"})
+		}
+	}
+	i.Verdicts = append(i.Verdicts, lifetimeVerdicts(i.NotBefore, i.Expiry, currentEpoch)...)
+	i.Context = DescribeSessionContext(st)
+	return i
+}
+
+// InspectEncodedToken works out whether data is a bearer or session token and inspects it against the network's current epoch
+func InspectEncodedToken(ctx context.Context, cli *client.Client, data []byte) (Inspection, error) {
+	info, err := client2.GetNetworkInfo(ctx, cli)
+	if err != nil {
+		return Inspection{}, fmt.Errorf("can't retrieve current epoch: %w", err)
+	}
+	epoch := info.CurrentEpoch()
+	// a session token always carries a session key, a bearer token always carries an eACL table
+	if st, err := DecodeSessionToken(data); err == nil && len(st.SessionKey()) > 0 {
+		return InspectSessionToken(st, epoch), nil
+	}
+	bt, err := DecodeBearerToken(data)
+	if err != nil {
+		return Inspection{}, err
+	}
+	if bt.EACLTable() == nil {
+		return Inspection{}, errors.New("data is neither a bearer nor a session token")
+	}
+	return InspectBearerToken(bt, epoch), nil
+}
+
+func lifetimeVerdicts(nbf, exp, currentEpoch uint64) []Verdict {
+	var verdicts []Verdict
+	if exp == 0 {
+		verdicts = append(verdicts, Verdict{false, "token has no expiry"})
+	} else if currentEpoch > exp {
+		verdicts = append(verdicts, Verdict{false, fmt.Sprintf("token expired at epoch %d, %d epochs ago", exp, currentEpoch-exp)})
+	} else {
+		verdicts = append(verdicts, Verdict{true, fmt.Sprintf("token expires at epoch %d, in %d epochs", exp, exp-currentEpoch)})
+	}
+	if currentEpoch < nbf {
+		verdicts = append(verdicts, Verdict{false, fmt.Sprintf("token is not valid before epoch %d", nbf)})
+	}
+	return verdicts
+}
+
+// DescribeRecord renders an eACL record on one line, e.g. "allow GET for keys [03ab..] where $Object:objectID == ..."
+func DescribeRecord(r *eacl.Record) string {
+	var targets []string
+	for _, t := range r.Targets() {
+		if len(t.BinaryKeys()) > 0 {
+			var ks []string
+			for _, k := range t.BinaryKeys() {
+				ks = append(ks, hex.EncodeToString(k))
+			}
+			targets = append(targets, "keys ["+strings.Join(ks, ", ")+"]")
+		} else {
+			targets = append(targets, "role "+t.Role().String())
+		}
+	}
+	s := fmt.Sprintf("%s %s for %s", strings.ToLower(r.Action().String()), r.Operation(), strings.Join(targets, ", "))
+	var filters []string
+	for _, f := range r.Filters() {
+		filters = append(filters, fmt.Sprintf("%s:%s %s %s", f.From(), f.Key(), f.Matcher(), f.Value()))
+	}
+	if len(filters) > 0 {
+		s += " where " + strings.Join(filters, " and ")
+	}
+	return s
+}
+
+// DescribeSessionContext lists what a session token allows and which container or object it is bound to
+func DescribeSessionContext(st *session.Token) []string {
+	switch c := st.Context().(type) {
+	case *session.ObjectContext:
+		scope := "all objects"
+		if addr := c.Address(); addr != nil {
+			if addr.ObjectID() != nil {
+				scope = "object " + addr.String()
+			} else if addr.ContainerID() != nil {
+				scope = "objects in container " + addr.ContainerID().String()
+			}
+		}
+		return []string{"object " + c.ToV2().GetVerb().String(), "applies to " + scope}
+	case *session.ContainerContext:
+		scope := "all containers"
+		if id := c.Container(); id != nil {
+			scope = "container " + id.String()
+		}
+		return []string{"container " + c.ToV2().Verb().String(), "applies to " + scope}
+	default:
+		if st.ToV2().GetBody().GetContext() == nil {
+			return []string{"no context, the token is not limited to an operation"}
+		}
+		return []string{"unsupported context " + fmt.Sprintf("%T", st.ToV2().GetBody().GetContext())}
+	}
+}
+
+func idString(id *owner.ID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
@@ -0,0 +1,46 @@
+package tokens_test
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/configwizard/gaspump-api/pkg/tokens"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeAndInspectBearerToken(t *testing.T) {
+	receiver, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	containerID := cid.ID{}
+	assert.Nil(t, containerID.Parse(testContainer), "error not nil")
+
+	bt, _, err := tokens.NewIssuer(nil, 10, nil).Issue(context.Background(), tokens.Request{
+		Template:    tokens.TemplateUploadDropBox,
+		ContainerID: containerID,
+		Receiver:    receiver.PublicKey(),
+	}, 100)
+	assert.Nil(t, err, "error not nil")
+
+	binary, err := bt.Marshal()
+	assert.Nil(t, err, "error not nil")
+	jsonData, err := bt.MarshalJSON()
+	assert.Nil(t, err, "error not nil")
+	encodings := [][]byte{binary, jsonData, []byte(base64.StdEncoding.EncodeToString(binary))}
+	for _, data := range encodings {
+		decoded, err := tokens.DecodeBearerToken(data)
+		assert.Nil(t, err, "error not nil")
+		assert.Equal(t, bt.Expiration(), decoded.Expiration())
+		assert.Equal(t, receiver.Address(), decoded.OwnerID().String())
+	}
+
+	inspection := tokens.InspectBearerToken(bt, 105)
+	assert.False(t, inspection.Valid(), "unsigned token reported as valid")
+	assert.False(t, inspection.SignatureValid)
+	assert.NotEmpty(t, inspection.Records)
+
+	expired := tokens.InspectBearerToken(bt, 111)
+	assert.Contains(t, expired.String(), "token expired at epoch 110")
+}
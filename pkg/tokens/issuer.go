@@ -0,0 +1,196 @@
+package tokens
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	client2 "github.com/configwizard/gaspump-api/pkg/client"
+	eacl2 "github.com/configwizard/gaspump-api/pkg/eacl"
+	"github.com/configwizard/gaspump-api/pkg/signer"
+	"github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neofs-sdk-go/client"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/eacl"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/token"
+)
+
+// Template names a pre-defined set of eACL rules a bearer token can be issued with
+type Template string
+
+const (
+	// TemplateReadOnlyShare lets the holder read everything in a container
+	TemplateReadOnlyShare Template = "read-only-share"
+	// TemplateUploadDropBox lets the holder put objects but not read them back
+	TemplateUploadDropBox Template = "upload-drop-box"
+	// TemplateObjectDownload lets the holder download a single object
+	TemplateObjectDownload Template = "object-download"
+	// TemplateAttributeScoped lets the holder read objects carrying a specific attribute
+	TemplateAttributeScoped Template = "attribute-scoped"
+)
+
+// DEFAULT_MAX_LIFETIME is the longest a token can live, in epochs, unless the issuer is told otherwise (roughly 4 days)
+const DEFAULT_MAX_LIFETIME = 100
+
+// Request describes the token a caller wants issued
+type Request struct {
+	Template    Template
+	ContainerID cid.ID
+	// ObjectID is required by TemplateObjectDownload
+	ObjectID *oid.ID
+	// AttributeKey and AttributeValue are required by TemplateAttributeScoped
+	AttributeKey   string
+	AttributeValue string
+	// Receiver is the public key of whoever will present the token
+	Receiver *keys.PublicKey
+	// Lifetime in epochs. Zero means the maximum the template allows
+	Lifetime uint64
+	// Sign with the issuer key. Leave false when the container owner signs with an external wallet
+	Sign bool
+}
+
+// Issuer produces bearer tokens from templates, caps their lifetime and records each one it hands out
+type Issuer struct {
+	signer      signer.Signer
+	maxLifetime uint64
+	limits      map[Template]uint64
+	audit       AuditLog
+}
+
+// NewIssuer creates an issuer. s signs for the container owner and may be nil if only unsigned tokens are issued.
+// A maxLifetime of 0 uses DEFAULT_MAX_LIFETIME and a nil audit log records nothing
+func NewIssuer(s signer.Signer, maxLifetime uint64, audit AuditLog) *Issuer {
+	if maxLifetime == 0 {
+		maxLifetime = DEFAULT_MAX_LIFETIME
+	}
+	return &Issuer{
+		signer:      s,
+		maxLifetime: maxLifetime,
+		limits:      make(map[Template]uint64),
+		audit:       audit,
+	}
+}
+
+// SetTemplateLimit lowers the maximum lifetime for a single template, e.g. to keep download links short lived
+func (i *Issuer) SetTemplateLimit(t Template, maxLifetime uint64) {
+	i.limits[t] = maxLifetime
+}
+
+// MaxLifetime returns the longest lifetime, in epochs, a token from this template can be issued with
+func (i *Issuer) MaxLifetime(t Template) uint64 {
+	if l, ok := i.limits[t]; ok && l < i.maxLifetime {
+		return l
+	}
+	return i.maxLifetime
+}
+
+// Table builds the eACL table for the request's template
+func (i *Issuer) Table(req Request) (eacl.Table, error) {
+	switch req.Template {
+	case TemplateReadOnlyShare:
+		return eacl2.ReadOnlyEACL(req.ContainerID, req.Receiver), nil
+	case TemplateUploadDropBox:
+		return eacl2.UploadOnlyEACL(req.ContainerID, req.Receiver), nil
+	case TemplateObjectDownload:
+		if req.ObjectID == nil {
+			return eacl.Table{}, errors.New("object download template requires an object ID")
+		}
+		return eacl2.SingleObjectReadEACL(req.ContainerID, *req.ObjectID, req.Receiver), nil
+	case TemplateAttributeScoped:
+		if req.AttributeKey == "" {
+			return eacl.Table{}, errors.New("attribute scoped template requires an attribute key")
+		}
+		return eacl2.AttributeReadEACL(req.ContainerID, req.AttributeKey, req.AttributeValue, req.Receiver), nil
+	default:
+		return eacl.Table{}, fmt.Errorf("unknown template %q", req.Template)
+	}
+}
+
+// Issue creates a bearer token from the request that is valid from currentEpoch.
+// The returned record has already been written to the audit log
+func (i *Issuer) Issue(ctx context.Context, req Request, currentEpoch uint64) (*token.BearerToken, Record, error) {
+	if req.Receiver == nil {
+		return nil, Record{}, errors.New("a token requires a receiver")
+	}
+	if req.Sign && i.signer == nil {
+		return nil, Record{}, errors.New("issuer has no signer")
+	}
+	max := i.MaxLifetime(req.Template)
+	lifetime := req.Lifetime
+	if lifetime == 0 {
+		lifetime = max
+	}
+	if lifetime > max {
+		return nil, Record{}, fmt.Errorf("lifetime of %d epochs exceeds the maximum of %d for %s", lifetime, max, req.Template)
+	}
+	table, err := i.Table(req)
+	if err != nil {
+		return nil, Record{}, err
+	}
+	receiver, err := wallet.OwnerIDFromPublicKey((*ecdsa.PublicKey)(req.Receiver))
+	if err != nil {
+		return nil, Record{}, err
+	}
+	bt, err := client2.NewBearerToken(ctx, receiver, currentEpoch+lifetime, table, nil)
+	if err != nil {
+		return nil, Record{}, err
+	}
+	bt.SetLifetime(currentEpoch+lifetime, currentEpoch, currentEpoch)
+	if req.Sign {
+		if err := client2.SignBearerToken(ctx, i.signer, bt); err != nil {
+			return nil, Record{}, err
+		}
+	}
+	id, err := TokenID(bt)
+	if err != nil {
+		return nil, Record{}, err
+	}
+	record := Record{
+		ID:        id,
+		Template:  req.Template,
+		Container: req.ContainerID.String(),
+		Owner:     receiver.String(),
+		Receiver:  hex.EncodeToString(req.Receiver.Bytes()),
+		IssuedAt:  currentEpoch,
+		Expiry:    currentEpoch + lifetime,
+		Signed:    req.Sign,
+		Created:   time.Now(),
+	}
+	if req.ObjectID != nil {
+		record.Object = req.ObjectID.String()
+	}
+	if req.Sign {
+		record.Issuer = bt.Issuer().String()
+	}
+	if i.audit != nil {
+		if err := i.audit.Record(record); err != nil {
+			return nil, Record{}, fmt.Errorf("can't record issued token: %w", err)
+		}
+	}
+	return bt, record, nil
+}
+
+// IssueFromNetwork issues a token that is valid from the network's current epoch
+func (i *Issuer) IssueFromNetwork(ctx context.Context, cli *client.Client, req Request) (*token.BearerToken, Record, error) {
+	info, err := client2.GetNetworkInfo(ctx, cli)
+	if err != nil {
+		return nil, Record{}, fmt.Errorf("can't retrieve current epoch: %w", err)
+	}
+	return i.Issue(ctx, req, info.CurrentEpoch())
+}
+
+// TokenID identifies a bearer token by the hash of its body, so it stays the same once signed
+func TokenID(bt *token.BearerToken) (string, error) {
+	body, err := bt.ToV2().GetBody().StableMarshal(nil)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(body)
+	return hex.EncodeToString(h[:]), nil
+}
@@ -0,0 +1,94 @@
+package tokens_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/configwizard/gaspump-api/pkg/signer"
+	"github.com/configwizard/gaspump-api/pkg/tokens"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/stretchr/testify/assert"
+)
+
+const testContainer = "HNhjKjd864CKBbce3voBMRu9j95rHCtTzHcycUMwuZTx"
+
+func TestIssueReadOnlyShare(t *testing.T) {
+	receiver, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	containerID := cid.ID{}
+	assert.Nil(t, containerID.Parse(testContainer), "error not nil")
+
+	audit := &tokens.MemoryAuditLog{}
+	issuer := tokens.NewIssuer(nil, 10, audit)
+	bt, record, err := issuer.Issue(context.Background(), tokens.Request{
+		Template:    tokens.TemplateReadOnlyShare,
+		ContainerID: containerID,
+		Receiver:    receiver.PublicKey(),
+	}, 100)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, uint64(110), bt.Expiration())
+	assert.Equal(t, uint64(100), bt.IssuedAt())
+	assert.Equal(t, receiver.Address(), bt.OwnerID().String())
+
+	records, err := audit.Records()
+	assert.Nil(t, err, "error not nil")
+	assert.Len(t, records, 1)
+	assert.Equal(t, record.ID, records[0].ID)
+	assert.False(t, records[0].Signed)
+}
+
+func TestIssueEnforcesLifetime(t *testing.T) {
+	receiver, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	containerID := cid.ID{}
+	assert.Nil(t, containerID.Parse(testContainer), "error not nil")
+
+	issuer := tokens.NewIssuer(nil, 10, nil)
+	issuer.SetTemplateLimit(tokens.TemplateUploadDropBox, 2)
+	_, _, err = issuer.Issue(context.Background(), tokens.Request{
+		Template:    tokens.TemplateUploadDropBox,
+		ContainerID: containerID,
+		Receiver:    receiver.PublicKey(),
+		Lifetime:    5,
+	}, 100)
+	assert.NotNil(t, err, "lifetime over the template limit was accepted")
+
+	_, _, err = issuer.Issue(context.Background(), tokens.Request{
+		Template:    tokens.TemplateObjectDownload,
+		ContainerID: containerID,
+		Receiver:    receiver.PublicKey(),
+	}, 100)
+	assert.NotNil(t, err, "object download without an object was accepted")
+
+	_, _, err = issuer.Issue(context.Background(), tokens.Request{
+		Template:    tokens.TemplateReadOnlyShare,
+		ContainerID: containerID,
+		Receiver:    receiver.PublicKey(),
+		Sign:        true,
+	}, 100)
+	assert.NotNil(t, err, "signed token without an issuer key was accepted")
+}
+
+func TestIssueSigned(t *testing.T) {
+	owner, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	receiver, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	containerID := cid.ID{}
+	assert.Nil(t, containerID.Parse(testContainer), "error not nil")
+
+	// the owner's key stays behind a remote signer, as it would in a browser wallet
+	remote := signer.NewRemoteSigner(signer.NewLocalSigner(owner, nil), owner.PublicKey(), signer.SchemeWalletConnect)
+	issuer := tokens.NewIssuer(remote, 10, nil)
+	bt, record, err := issuer.Issue(context.Background(), tokens.Request{
+		Template:    tokens.TemplateUploadDropBox,
+		ContainerID: containerID,
+		Receiver:    receiver.PublicKey(),
+		Sign:        true,
+	}, 100)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, owner.Address(), record.Issuer)
+	inspection := tokens.InspectBearerToken(bt, 105)
+	assert.True(t, inspection.SignatureValid, inspection.String())
+}
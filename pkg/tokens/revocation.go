@@ -0,0 +1,402 @@
+package tokens
+
+import (
+	"bytes"
+	"context"
+	"crypto/elliptic"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	client2 "github.com/configwizard/gaspump-api/pkg/client"
+	container2 "github.com/configwizard/gaspump-api/pkg/container"
+	eacl2 "github.com/configwizard/gaspump-api/pkg/eacl"
+	object2 "github.com/configwizard/gaspump-api/pkg/object"
+	"github.com/configwizard/gaspump-api/pkg/signer"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neofs-sdk-go/client"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/eacl"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/owner"
+	"github.com/nspcc-dev/neofs-sdk-go/session"
+	"github.com/nspcc-dev/neofs-sdk-go/token"
+)
+
+// RevocationListAttribute marks the objects holding a revocation list. Its value is the list's sequence number
+const RevocationListAttribute = "RevocationList"
+
+var (
+	ErrTokenRevoked     = errors.New("token has been revoked")
+	ErrTokenExpired     = errors.New("token has expired")
+	ErrTokenNotYetValid = errors.New("token is not valid yet")
+)
+
+// RevokedToken is an entry in a revocation list. A token matches if its ID, owner or holder key matches
+type RevokedToken struct {
+	ID    string `json:"id,omitempty"`
+	Owner string `json:"owner,omitempty"`
+	Key   string `json:"key,omitempty"`
+	// Expiry of the revoked token, after which the entry can be pruned. 0 keeps it forever
+	Expiry    uint64 `json:"expiry"`
+	RevokedAt uint64 `json:"revokedAt"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// RevocationList is a list of withdrawn bearer tokens, signed by the container owner and stored in the container
+type RevocationList struct {
+	Container string         `json:"container"`
+	Sequence  uint64         `json:"sequence"`
+	Entries   []RevokedToken `json:"entries"`
+	Key       string         `json:"key,omitempty"`
+	Scheme    signer.Scheme  `json:"scheme,omitempty"`
+	Signature string         `json:"signature,omitempty"`
+}
+
+func NewRevocationList(containerID cid.ID) *RevocationList {
+	return &RevocationList{Container: containerID.String()}
+}
+
+// RevokeToken adds a bearer token to the list. holderKey is optional but without it the holder can't be excluded from the container eACL
+func (l *RevocationList) RevokeToken(bt *token.BearerToken, holderKey *keys.PublicKey, currentEpoch uint64, reason string) error {
+	id, err := TokenID(bt)
+	if err != nil {
+		return err
+	}
+	entry := RevokedToken{
+		ID:        id,
+		Expiry:    bt.Expiration(),
+		RevokedAt: currentEpoch,
+		Reason:    reason,
+	}
+	if bt.OwnerID() != nil {
+		entry.Owner = bt.OwnerID().String()
+	}
+	if holderKey != nil {
+		entry.Key = hex.EncodeToString(holderKey.Bytes())
+	}
+	l.add(entry)
+	return nil
+}
+
+// RevokeRecord adds a token the Issuer handed out, using its audit record
+func (l *RevocationList) RevokeRecord(r Record, currentEpoch uint64, reason string) {
+	l.add(RevokedToken{
+		ID:        r.ID,
+		Owner:     r.Owner,
+		Key:       r.Receiver,
+		Expiry:    r.Expiry,
+		RevokedAt: currentEpoch,
+		Reason:    reason,
+	})
+}
+
+// RevokeKey withdraws every token held by the key until the untilEpoch (0 for good)
+func (l *RevocationList) RevokeKey(key *keys.PublicKey, untilEpoch, currentEpoch uint64, reason string) {
+	l.add(RevokedToken{
+		Owner:     key.Address(),
+		Key:       hex.EncodeToString(key.Bytes()),
+		Expiry:    untilEpoch,
+		RevokedAt: currentEpoch,
+		Reason:    reason,
+	})
+}
+
+func (l *RevocationList) add(entry RevokedToken) {
+	l.Entries = append(l.Entries, entry)
+	l.Sequence++
+	l.Key, l.Scheme, l.Signature = "", "", ""
+}
+
+// Prune drops entries for tokens that have expired anyway. The list must be signed again if anything was removed
+func (l *RevocationList) Prune(currentEpoch uint64) int {
+	var kept []RevokedToken
+	for _, e := range l.Entries {
+		if e.Expiry == 0 || e.Expiry >= currentEpoch {
+			kept = append(kept, e)
+		}
+	}
+	removed := len(l.Entries) - len(kept)
+	if removed > 0 {
+		l.Entries = kept
+		l.Sequence++
+		l.Key, l.Scheme, l.Signature = "", "", ""
+	}
+	return removed
+}
+
+// IsRevoked reports whether the token matches an entry in the list that is still in force at currentEpoch
+func (l *RevocationList) IsRevoked(bt *token.BearerToken, currentEpoch uint64) bool {
+	id, _ := TokenID(bt)
+	var tokenOwner string
+	if bt.OwnerID() != nil {
+		tokenOwner = bt.OwnerID().String()
+	}
+	for _, e := range l.Entries {
+		if e.expired(currentEpoch) {
+			continue
+		}
+		if e.ID != "" {
+			if e.ID == id {
+				return true
+			}
+			continue
+		}
+		if e.Owner != "" && e.Owner == tokenOwner {
+			return true
+		}
+	}
+	return false
+}
+
+// RevokedKeys returns the holder keys of every entry that has one and is still in force at currentEpoch
+func (l *RevocationList) RevokedKeys(currentEpoch uint64) keys.PublicKeys {
+	var revoked keys.PublicKeys
+	seen := make(map[string]bool)
+	for _, e := range l.Entries {
+		if e.Key == "" || seen[e.Key] || e.expired(currentEpoch) {
+			continue
+		}
+		k, err := keys.NewPublicKeyFromString(e.Key)
+		if err != nil {
+			continue
+		}
+		seen[e.Key] = true
+		revoked = append(revoked, k)
+	}
+	return revoked
+}
+
+// expired entries are kept until Prune but no longer revoke anything
+func (e RevokedToken) expired(currentEpoch uint64) bool {
+	return e.Expiry != 0 && e.Expiry < currentEpoch
+}
+
+func (l RevocationList) signedData() ([]byte, error) {
+	l.Key, l.Signature = "", ""
+	return json.Marshal(l)
+}
+
+// Sign signs the list with the container owner's signer, in its default scheme. The scheme is part of the signed data
+func (l *RevocationList) Sign(ctx context.Context, s signer.Signer) error {
+	l.Key, l.Scheme, l.Signature = "", signer.DefaultScheme(s), ""
+	data, err := l.signedData()
+	if err != nil {
+		return err
+	}
+	sig, err := s.Sign(signer.WithPayloadType(ctx, signer.PayloadRaw), l.Scheme, data)
+	if err != nil {
+		l.Scheme = ""
+		return fmt.Errorf("can't sign revocation list: %w", err)
+	}
+	l.Key = hex.EncodeToString(s.PublicKey().Bytes())
+	l.Signature = hex.EncodeToString(sig)
+	return nil
+}
+
+// Verify checks the list was signed by containerOwner in its Scheme. Lists without one were signed with ECDSA_RFC6979_SHA256
+func (l *RevocationList) Verify(containerOwner *keys.PublicKey) error {
+	if l.Signature == "" {
+		return errors.New("revocation list is not signed")
+	}
+	key, err := keys.NewPublicKeyFromString(l.Key)
+	if err != nil || !key.Equal(containerOwner) {
+		return errors.New("revocation list was not signed by the container owner")
+	}
+	sig, err := hex.DecodeString(l.Signature)
+	if err != nil {
+		return fmt.Errorf("can't decode revocation list signature: %w", err)
+	}
+	data, err := l.signedData()
+	if err != nil {
+		return err
+	}
+	scheme := l.Scheme
+	if scheme == "" {
+		scheme = signer.SchemeRFC6979
+	}
+	if err := signer.Verify(containerOwner.Bytes(), scheme, data, sig); err != nil {
+		return fmt.Errorf("revocation list signature is invalid: %w", err)
+	}
+	return nil
+}
+
+// PutRevocationList stores a signed list as an object in its container
+func PutRevocationList(ctx context.Context, cli *client.Client, list *RevocationList, ownerID *owner.ID, bearerToken *token.BearerToken, sessionToken *session.Token) (oid.ID, error) {
+	if list.Signature == "" {
+		return oid.ID{}, errors.New("revocation list must be signed before it is stored")
+	}
+	containerID := cid.ID{}
+	if err := containerID.Parse(list.Container); err != nil {
+		return oid.ID{}, fmt.Errorf("revocation list has an invalid container: %w", err)
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		return oid.ID{}, err
+	}
+	listAttr := object.NewAttribute()
+	listAttr.SetKey(RevocationListAttribute)
+	listAttr.SetValue(strconv.FormatUint(list.Sequence, 10))
+	fileNameAttr := object.NewAttribute()
+	fileNameAttr.SetKey(object.AttributeFileName)
+	fileNameAttr.SetValue("revocations.json")
+	timestampAttr := object.NewAttribute()
+	timestampAttr.SetKey(object.AttributeTimestamp)
+	timestampAttr.SetValue(strconv.FormatInt(time.Now().Unix(), 10))
+
+	reader := (io.Reader)(bytes.NewReader(data))
+	return object2.UploadObject(ctx, cli, len(data), containerID, ownerID, []*object.Attribute{listAttr, fileNameAttr, timestampAttr}, bearerToken, sessionToken, &reader)
+}
+
+// GetRevocationList returns the newest list in the container signed by containerOwner, or an empty list if there is none
+func GetRevocationList(ctx context.Context, cli *client.Client, containerID cid.ID, containerOwner *keys.PublicKey, bearerToken *token.BearerToken, sessionToken *session.Token) (*RevocationList, error) {
+	filters := object.SearchFilters{}
+	filters.AddRootFilter()
+	filters.AddFilter(RevocationListAttribute, "", object.MatchCommonPrefix)
+	ids, err := object2.QueryObjects(ctx, cli, containerID, filters, bearerToken, sessionToken)
+	if err != nil {
+		return nil, fmt.Errorf("can't search for revocation lists: %w", err)
+	}
+	var lists []*RevocationList
+	for _, id := range ids {
+		head, err := object2.GetObjectMetaData(ctx, cli, id, containerID, bearerToken, sessionToken)
+		if err != nil {
+			continue
+		}
+		buf := new(bytes.Buffer)
+		writer := (io.Writer)(buf)
+		if _, err := object2.GetObject(ctx, cli, int(head.PayloadSize()), id, containerID, bearerToken, sessionToken, &writer); err != nil {
+			continue
+		}
+		list := new(RevocationList)
+		if err := json.Unmarshal(buf.Bytes(), list); err != nil {
+			continue
+		}
+		// anyone with put access could store a list, only trust the owner's
+		if list.Container != containerID.String() || list.Verify(containerOwner) != nil {
+			continue
+		}
+		lists = append(lists, list)
+	}
+	if len(lists) == 0 {
+		return NewRevocationList(containerID), nil
+	}
+	sort.Slice(lists, func(i, j int) bool { return lists[i].Sequence > lists[j].Sequence })
+	return lists[0], nil
+}
+
+// RevocationEACL returns a copy of table with deny records for every key revoked at currentEpoch placed at its head.
+// The records a rotation with previous added are replaced, they are told apart from deny records of the owner by
+// targeting only keys previous revoked. previous is nil on the first rotation.
+// The container eACL is only consulted for requests without a bearer token, so holders must also be refused by gateways with VerifyPresentedToken
+func RevocationEACL(table eacl.Table, previous, list *RevocationList, currentEpoch uint64) eacl.Table {
+	rotated := eacl.NewTable()
+	rotated.SetCID(table.CID())
+	revoked := list.RevokedKeys(currentEpoch)
+	if len(revoked) > 0 {
+		target := eacl.NewTarget()
+		var binaryKeys [][]byte
+		for _, k := range revoked {
+			binaryKeys = append(binaryKeys, k.Bytes())
+		}
+		target.SetBinaryKeys(binaryKeys)
+		eacl2.AddRecords(rotated, eacl.ActionDeny, target, append(eacl2.ReadOperations, eacl2.WriteOperations...))
+	}
+	records := table.Records()
+	if previous != nil {
+		previousKeys := previous.everRevokedKeys()
+		for len(records) > 0 && isRevocationRecord(records[0], previousKeys) {
+			records = records[1:]
+		}
+	}
+	for _, r := range records {
+		rotated.AddRecord(r)
+	}
+	return *rotated
+}
+
+// RotateContainerEACL sets the container eACL to exclude every key on the revocation list, replacing the records
+// of the rotation with previous. Must be called by the container owner
+func RotateContainerEACL(ctx context.Context, cli *client.Client, containerID cid.ID, previous, list *RevocationList, currentEpoch uint64) error {
+	current, err := container2.GetEACL(ctx, cli, containerID)
+	if err != nil {
+		return err
+	}
+	table := RevocationEACL(*current, previous, list, currentEpoch)
+	table.SetCID(&containerID)
+	return container2.SetEACLOnContainer(ctx, cli, containerID, table)
+}
+
+// VerifyPresentedToken is what a gateway should call before honouring a bearer token for a container owned by
+// containerOwner. The token must be issued, that is signed, by the container owner, and list is only trusted
+// if the owner signed it too. The token's OwnerID names its holder, so the issuer is told by the signature key
+func VerifyPresentedToken(bt *token.BearerToken, containerOwner *keys.PublicKey, list *RevocationList, currentEpoch uint64) error {
+	sig := bt.Signature()
+	if sig == nil || len(sig.Key()) == 0 {
+		return errors.New("token is not signed")
+	}
+	issuer, err := keys.NewPublicKeyFromBytes(sig.Key(), elliptic.P256())
+	if err != nil {
+		return fmt.Errorf("token has an invalid signature key: %w", err)
+	}
+	if !issuer.Equal(containerOwner) {
+		return errors.New("token was not issued by the container owner")
+	}
+	if err := client2.VerifyBearerTokenSignature(bt); err != nil {
+		return fmt.Errorf("token signature is invalid: %w", err)
+	}
+	if bt.Expiration() < currentEpoch {
+		return ErrTokenExpired
+	}
+	if bt.NotBeforeTime() > currentEpoch {
+		return ErrTokenNotYetValid
+	}
+	if list == nil {
+		return nil
+	}
+	if err := list.Verify(containerOwner); err != nil {
+		return fmt.Errorf("can't trust revocation list: %w", err)
+	}
+	if list.Container != "" && bt.EACLTable() != nil && bt.EACLTable().CID() != nil && bt.EACLTable().CID().String() != list.Container {
+		return errors.New("revocation list is for a different container than the token")
+	}
+	if list.IsRevoked(bt, currentEpoch) {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+// everRevokedKeys returns every holder key of the list, expired or not, in hex
+func (l *RevocationList) everRevokedKeys() map[string]bool {
+	revoked := make(map[string]bool)
+	for _, e := range l.Entries {
+		if k, err := keys.NewPublicKeyFromString(e.Key); err == nil {
+			revoked[hex.EncodeToString(k.Bytes())] = true
+		}
+	}
+	return revoked
+}
+
+// isRevocationRecord reports whether r is a deny record a rotation added: no filters and a single target
+// of nothing but keys in revoked
+func isRevocationRecord(r *eacl.Record, revoked map[string]bool) bool {
+	if r.Action() != eacl.ActionDeny || len(r.Filters()) > 0 || len(r.Targets()) != 1 {
+		return false
+	}
+	binaryKeys := r.Targets()[0].BinaryKeys()
+	if len(binaryKeys) == 0 {
+		return false
+	}
+	for _, k := range binaryKeys {
+		if !revoked[hex.EncodeToString(k)] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,153 @@
+package tokens_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	eacl2 "github.com/configwizard/gaspump-api/pkg/eacl"
+	"github.com/configwizard/gaspump-api/pkg/signer"
+	"github.com/configwizard/gaspump-api/pkg/tokens"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/eacl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevocationList(t *testing.T) {
+	containerOwner, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	receiver, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	containerID := cid.ID{}
+	assert.Nil(t, containerID.Parse(testContainer), "error not nil")
+
+	issuer := tokens.NewIssuer(nil, 10, nil)
+	request := tokens.Request{
+		Template:    tokens.TemplateReadOnlyShare,
+		ContainerID: containerID,
+		Receiver:    receiver.PublicKey(),
+	}
+	revokedToken, record, err := issuer.Issue(context.Background(), request, 100)
+	assert.Nil(t, err, "error not nil")
+	request.Lifetime = 5
+	otherToken, _, err := issuer.Issue(context.Background(), request, 100)
+	assert.Nil(t, err, "error not nil")
+
+	list := tokens.NewRevocationList(containerID)
+	list.RevokeRecord(record, 101, "leaked")
+	assert.True(t, list.IsRevoked(revokedToken, 101))
+	assert.False(t, list.IsRevoked(otherToken, 101))
+	assert.False(t, list.IsRevoked(revokedToken, 111), "expired entry still revokes")
+
+	assert.Nil(t, list.Sign(context.Background(), signer.NewKeySigner(&containerOwner.PrivateKey)), "error not nil")
+	assert.Nil(t, list.Verify(containerOwner.PublicKey()), "signature invalid")
+	assert.Equal(t, signer.SchemeSHA512, list.Scheme)
+	assert.NotNil(t, list.Verify(receiver.PublicKey()), "list verified against the wrong key")
+	list.Scheme = signer.SchemeRFC6979
+	assert.NotNil(t, list.Verify(containerOwner.PublicKey()), "list verified in another scheme")
+	list.Scheme = signer.SchemeSHA512
+	list.Entries[0].Reason = "tampered"
+	assert.NotNil(t, list.Verify(containerOwner.PublicKey()), "tampered list verified")
+
+	assert.Equal(t, 0, list.Prune(110))
+	assert.Equal(t, 1, list.Prune(111))
+	assert.Empty(t, list.Entries)
+}
+
+func TestRevocationListWithoutScheme(t *testing.T) {
+	containerOwner, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	containerID := cid.ID{}
+	assert.Nil(t, containerID.Parse(testContainer), "error not nil")
+	list := tokens.NewRevocationList(containerID)
+	list.RevokeKey(containerOwner.PublicKey(), 0, 100, "lost")
+
+	// lists signed before the scheme was recorded carry an RFC6979 signature
+	data, err := json.Marshal(list)
+	assert.Nil(t, err, "error not nil")
+	list.Key = hex.EncodeToString(containerOwner.PublicKey().Bytes())
+	list.Signature = hex.EncodeToString(containerOwner.Sign(data))
+	assert.Nil(t, list.Verify(containerOwner.PublicKey()), "signature invalid")
+}
+
+func TestRevocationEACL(t *testing.T) {
+	receiver, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	containerID := cid.ID{}
+	assert.Nil(t, containerID.Parse(testContainer), "error not nil")
+
+	other, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	blocked, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+
+	// a deny record the owner wrote looks like a revocation record but must survive rotations
+	base := eacl.NewTable()
+	base.SetCID(&containerID)
+	ownerDeny := eacl.CreateRecord(eacl.ActionDeny, eacl.OperationGet)
+	eacl.AddFormedTarget(ownerDeny, eacl.RoleUnknown, (ecdsa.PublicKey)(*blocked.PublicKey()))
+	base.AddRecord(ownerDeny)
+	for _, r := range eacl2.PutAllowDenyOthersEACL(containerID, nil).Records() {
+		base.AddRecord(r)
+	}
+
+	list := tokens.NewRevocationList(containerID)
+	list.RevokeKey(receiver.PublicKey(), 0, 100, "")
+	rotated := tokens.RevocationEACL(*base, nil, list, 100)
+	assert.Len(t, rotated.Records(), len(base.Records())+7)
+
+	// rotating again must replace the previous revocation records rather than stack them
+	again := tokens.RevocationEACL(rotated, list, list, 100)
+	assert.True(t, eacl2.EqualRecords(rotated.Records(), again.Records()))
+
+	next := *list
+	next.Entries = append([]tokens.RevokedToken{}, list.Entries...)
+	next.RevokeKey(other.PublicKey(), 105, 101, "")
+	rotated = tokens.RevocationEACL(again, list, &next, 101)
+	assert.Len(t, rotated.Records(), len(base.Records())+7)
+	assert.Len(t, rotated.Records()[0].Targets()[0].BinaryKeys(), 2)
+	assert.True(t, eacl2.EqualRecords(base.Records(), rotated.Records()[7:]), "owner records changed")
+
+	// expired entries are left out of the eACL
+	rotated = tokens.RevocationEACL(rotated, &next, &next, 106)
+	assert.Len(t, rotated.Records()[0].Targets()[0].BinaryKeys(), 1)
+	assert.True(t, eacl2.EqualRecords(base.Records(), rotated.Records()[7:]), "owner records changed")
+}
+
+func TestVerifyPresentedToken(t *testing.T) {
+	containerOwner, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	stranger, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	receiver, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	containerID := cid.ID{}
+	assert.Nil(t, containerID.Parse(testContainer), "error not nil")
+	request := tokens.Request{
+		Template:    tokens.TemplateReadOnlyShare,
+		ContainerID: containerID,
+		Receiver:    receiver.PublicKey(),
+		Sign:        true,
+	}
+
+	bt, record, err := tokens.NewIssuer(signer.NewKeySigner(&containerOwner.PrivateKey), 10, nil).Issue(context.Background(), request, 100)
+	assert.Nil(t, err, "error not nil")
+	assert.Nil(t, tokens.VerifyPresentedToken(bt, containerOwner.PublicKey(), nil, 105), "error not nil")
+	assert.True(t, errors.Is(tokens.VerifyPresentedToken(bt, containerOwner.PublicKey(), nil, 111), tokens.ErrTokenExpired), "expired token accepted")
+
+	forged, _, err := tokens.NewIssuer(signer.NewKeySigner(&stranger.PrivateKey), 10, nil).Issue(context.Background(), request, 100)
+	assert.Nil(t, err, "error not nil")
+	assert.NotNil(t, tokens.VerifyPresentedToken(forged, containerOwner.PublicKey(), nil, 105), "token of another issuer accepted")
+
+	list := tokens.NewRevocationList(containerID)
+	list.RevokeRecord(record, 101, "leaked")
+	assert.NotNil(t, tokens.VerifyPresentedToken(bt, containerOwner.PublicKey(), list, 105), "unsigned list trusted")
+	assert.Nil(t, list.Sign(context.Background(), signer.NewKeySigner(&stranger.PrivateKey)), "error not nil")
+	assert.NotNil(t, tokens.VerifyPresentedToken(bt, containerOwner.PublicKey(), list, 105), "list of another key trusted")
+	assert.Nil(t, list.Sign(context.Background(), signer.NewKeySigner(&containerOwner.PrivateKey)), "error not nil")
+	assert.True(t, errors.Is(tokens.VerifyPresentedToken(bt, containerOwner.PublicKey(), list, 105), tokens.ErrTokenRevoked), "revoked token accepted")
+}
@@ -0,0 +1,213 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+)
+
+// ErrPrecisionLoss is returned when an amount has more decimal places than the token supports
+var ErrPrecisionLoss = errors.New("amount has more decimal places than the token")
+
+// Amount is a fixed point token amount: Value base units of 10^-Decimals tokens. The zero Amount is 0.
+// Amounts are values, the methods never change the receiver
+type Amount struct {
+	value    *big.Int
+	decimals int64
+}
+
+// NewAmount makes an amount of value base units
+func NewAmount(value *big.Int, decimals int64) Amount {
+	return Amount{value: new(big.Int).Set(value), decimals: decimals}
+}
+
+// AmountFromInt64 makes an amount of value base units, e.g. AmountFromInt64(150000000, 8) is 1.5 GAS
+func AmountFromInt64(value int64, decimals int64) Amount {
+	return Amount{value: big.NewInt(value), decimals: decimals}
+}
+
+// ParseAmount parses a decimal number such as "1.5" or "-0.25" into an amount with decimals places
+func ParseAmount(s string, decimals int64) (Amount, error) {
+	if decimals < 0 {
+		return Amount{}, fmt.Errorf("invalid decimals %d", decimals)
+	}
+	s = strings.TrimSpace(s)
+	num := strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	whole, frac := num, ""
+	if i := strings.IndexByte(num, '.'); i >= 0 {
+		whole, frac = num[:i], num[i+1:]
+	}
+	if whole == "" && frac == "" || strings.ContainsAny(whole+frac, "+-") {
+		return Amount{}, fmt.Errorf("invalid amount %q", s)
+	}
+	if trimmed := strings.TrimRight(frac, "0"); int64(len(trimmed)) > decimals {
+		return Amount{}, fmt.Errorf("%w: %q has more than %d", ErrPrecisionLoss, s, decimals)
+	} else {
+		frac = trimmed + strings.Repeat("0", int(decimals)-len(trimmed))
+	}
+	v, ok := new(big.Int).SetString("0"+whole+frac, 10)
+	if !ok {
+		return Amount{}, fmt.Errorf("invalid amount %q", s)
+	}
+	if strings.HasPrefix(s, "-") {
+		v.Neg(v)
+	}
+	return Amount{value: v, decimals: decimals}, nil
+}
+
+// ParseTokenAmount parses an amount of token, with or without its symbol, e.g. "1.5 GAS" or "1.5"
+func ParseTokenAmount(s string, token *wallet.Token) (Amount, error) {
+	fields := strings.Fields(s)
+	switch {
+	case len(fields) == 2 && strings.EqualFold(fields[1], token.Symbol):
+	case len(fields) == 1:
+	default:
+		return Amount{}, fmt.Errorf("invalid %s amount %q", token.Symbol, s)
+	}
+	return ParseAmount(fields[0], token.Decimals)
+}
+
+func (a Amount) big() *big.Int {
+	if a.value == nil {
+		return new(big.Int)
+	}
+	return a.value
+}
+
+// Value is the amount in base units
+func (a Amount) Value() *big.Int {
+	return new(big.Int).Set(a.big())
+}
+
+func (a Amount) Decimals() int64 {
+	return a.decimals
+}
+
+// Int64 is the amount in base units, ok is false if it doesn't fit
+func (a Amount) Int64() (v int64, ok bool) {
+	return a.big().Int64(), a.big().IsInt64()
+}
+
+// Rescale converts to another number of decimal places. It fails rather than drop digits
+func (a Amount) Rescale(decimals int64) (Amount, error) {
+	if decimals < 0 {
+		return Amount{}, fmt.Errorf("invalid decimals %d", decimals)
+	}
+	v := new(big.Int).Set(a.big())
+	if decimals >= a.decimals {
+		v.Mul(v, pow10(decimals-a.decimals))
+		return Amount{value: v, decimals: decimals}, nil
+	}
+	q, r := new(big.Int).QuoRem(v, pow10(a.decimals-decimals), new(big.Int))
+	if r.Sign() != 0 {
+		return Amount{}, fmt.Errorf("%w: %s has more than %d", ErrPrecisionLoss, a, decimals)
+	}
+	return Amount{value: q, decimals: decimals}, nil
+}
+
+// Truncate converts to another number of decimal places, dropping the digits that don't fit
+func (a Amount) Truncate(decimals int64) Amount {
+	v := new(big.Int).Set(a.big())
+	if decimals >= a.decimals {
+		return Amount{value: v.Mul(v, pow10(decimals-a.decimals)), decimals: decimals}
+	}
+	return Amount{value: v.Quo(v, pow10(a.decimals-decimals)), decimals: decimals}
+}
+
+// align brings a and b to the larger number of decimal places
+func align(a, b Amount) (*big.Int, *big.Int, int64) {
+	if a.decimals < b.decimals {
+		a, _ = a.Rescale(b.decimals)
+	} else if b.decimals < a.decimals {
+		b, _ = b.Rescale(a.decimals)
+	}
+	return a.big(), b.big(), a.decimals
+}
+
+// Add returns a+b at the larger number of decimal places of the two
+func (a Amount) Add(b Amount) Amount {
+	x, y, d := align(a, b)
+	return Amount{value: new(big.Int).Add(x, y), decimals: d}
+}
+
+// Sub returns a-b at the larger number of decimal places of the two
+func (a Amount) Sub(b Amount) Amount {
+	x, y, d := align(a, b)
+	return Amount{value: new(big.Int).Sub(x, y), decimals: d}
+}
+
+// Cmp compares the amounts by value, whatever their decimals: -1 if a < b, 0 if equal, 1 if a > b
+func (a Amount) Cmp(b Amount) int {
+	x, y, _ := align(a, b)
+	return x.Cmp(y)
+}
+
+func (a Amount) Sign() int {
+	return a.big().Sign()
+}
+
+func (a Amount) IsZero() bool {
+	return a.Sign() == 0
+}
+
+// String formats the amount with trailing zeros dropped, e.g. 1.5
+func (a Amount) String() string {
+	s := a.fixed()
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// Format appends the token symbol, e.g. 1.5 GAS
+func (a Amount) Format(symbol string) string {
+	return a.String() + " " + symbol
+}
+
+// fixed formats with every decimal place, which keeps the decimals when parsed back
+func (a Amount) fixed() string {
+	v := a.big()
+	sign := ""
+	if v.Sign() < 0 {
+		sign = "-"
+	}
+	digits := new(big.Int).Abs(v).String()
+	if a.decimals <= 0 {
+		return sign + digits
+	}
+	if pad := int(a.decimals) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	return sign + digits[:len(digits)-int(a.decimals)] + "." + digits[len(digits)-int(a.decimals):]
+}
+
+// MarshalJSON writes the amount as a string with all its decimal places, e.g. "1.50000000"
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.fixed())
+}
+
+// UnmarshalJSON reads a decimal string, taking the decimals from the number of fractional digits
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	decimals := int64(0)
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		decimals = int64(len(s) - i - 1)
+	}
+	parsed, err := ParseAmount(s, decimals)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil)
+}
@@ -0,0 +1,96 @@
+package wallet_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/configwizard/gaspump-api/pkg/wallet"
+	neowallet "github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAmount(t *testing.T) {
+	for _, c := range []struct {
+		in       string
+		decimals int64
+		value    int64
+		str      string
+	}{
+		{"1.5", 8, 150_000_000, "1.5"},
+		{"1", 8, 100_000_000, "1"},
+		{"0.00000001", 8, 1, "0.00000001"},
+		{".5", 1, 5, "0.5"},
+		{"-2.50", 2, -250, "-2.5"},
+		{"+3", 0, 3, "3"},
+		{"10.000", 0, 10, "10"},
+	} {
+		a, err := wallet.ParseAmount(c.in, c.decimals)
+		assert.Nil(t, err, "error not nil")
+		v, ok := a.Int64()
+		assert.True(t, ok)
+		assert.Equal(t, c.value, v, c.in)
+		assert.Equal(t, c.str, a.String(), c.in)
+	}
+
+	for _, in := range []string{"", ".", "abc", "1.2.3", "1e5", "--1", "1-"} {
+		_, err := wallet.ParseAmount(in, 8)
+		assert.NotNil(t, err, in)
+	}
+	_, err := wallet.ParseAmount("0.000000001", 8)
+	assert.True(t, errors.Is(err, wallet.ErrPrecisionLoss), "precision lost")
+
+	gas := &neowallet.Token{Symbol: "GAS", Decimals: 8}
+	a, err := wallet.ParseTokenAmount("1.5 gas", gas)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, "1.5 GAS", a.Format(gas.Symbol))
+	_, err = wallet.ParseTokenAmount("1.5 NEO", gas)
+	assert.NotNil(t, err, "wrong symbol accepted")
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	a := wallet.AmountFromInt64(150_000_000, 8)
+	b := wallet.AmountFromInt64(25, 2)
+	sum := a.Add(b)
+	assert.Equal(t, "1.75", sum.String())
+	assert.Equal(t, int64(8), sum.Decimals())
+	assert.Equal(t, "1.25", a.Sub(b).String())
+	assert.Equal(t, "-1.25", b.Sub(a).String())
+	assert.Equal(t, 1, a.Cmp(b))
+	assert.Equal(t, 0, wallet.AmountFromInt64(1, 0).Cmp(wallet.AmountFromInt64(100_000_000, 8)))
+	// arithmetic doesn't change the operands
+	assert.Equal(t, "1.5", a.String())
+
+	var zero wallet.Amount
+	assert.True(t, zero.IsZero())
+	assert.Equal(t, "0", zero.String())
+	assert.Equal(t, "0.25", zero.Add(b).String())
+
+	whole, err := wallet.AmountFromInt64(300_000_000, 8).Rescale(0)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, big.NewInt(3), whole.Value())
+	_, err = a.Rescale(0)
+	assert.True(t, errors.Is(err, wallet.ErrPrecisionLoss), "precision lost")
+	assert.Equal(t, big.NewInt(1), a.Truncate(0).Value())
+	assert.Equal(t, big.NewInt(-1), wallet.AmountFromInt64(-150_000_000, 8).Truncate(0).Value())
+	assert.Equal(t, big.NewInt(1_500_000_000_000), a.Truncate(12).Value())
+}
+
+func TestAmountJSON(t *testing.T) {
+	a := wallet.AmountFromInt64(150_000_000, 8)
+	data, err := json.Marshal(a)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, `"1.50000000"`, string(data))
+
+	var back wallet.Amount
+	assert.Nil(t, json.Unmarshal(data, &back), "error not nil")
+	assert.Equal(t, 0, a.Cmp(back))
+	assert.Equal(t, int64(8), back.Decimals())
+
+	tokens := wallet.Nep17Tokens{Symbol: "GAS", Amount: wallet.AmountFromInt64(-5, 2)}
+	data, err = json.Marshal(tokens)
+	assert.Nil(t, err, "error not nil")
+	assert.Contains(t, string(data), `"amount":"-0.05"`)
+	assert.NotNil(t, json.Unmarshal([]byte(`"1.2.3"`), &back), "invalid amount accepted")
+}
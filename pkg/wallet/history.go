@@ -0,0 +1,155 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// DEFAULT_HISTORY_PAGE_SIZE is how many transfers a history page holds when HistoryQuery.Limit is 0
+const DEFAULT_HISTORY_PAGE_SIZE = 50
+
+type TransferDirection string
+
+const (
+	TRANSFER_SENT     TransferDirection = "sent"
+	TRANSFER_RECEIVED TransferDirection = "received"
+)
+
+// HistoryQuery selects a page of transfers. Zero From and To cover the whole history, rather than the node's
+// default of the last week. Page counts from 0
+type HistoryQuery struct {
+	From  time.Time
+	To    time.Time
+	Limit int
+	Page  int
+}
+
+// Nep17HistoryEntry is one side of a NEP-17 transfer as seen by the queried address
+type Nep17HistoryEntry struct {
+	TxHash      util.Uint256      `json:"txHash"`
+	Block       uint32            `json:"block"`
+	NotifyIndex uint32            `json:"notifyIndex"`
+	Time        time.Time         `json:"time"`
+	Direction   TransferDirection `json:"direction"`
+	// Counterparty is the address on the other side, empty for mints and burns
+	Counterparty string       `json:"counterparty"`
+	Token        util.Uint160 `json:"token"`
+	Symbol       string       `json:"symbol"`
+	Amount       Amount       `json:"amount"`
+}
+
+// Nep17HistoryPage is a page of transfers, newest first. More is set if the next page may hold further transfers
+type Nep17HistoryPage struct {
+	Address string              `json:"address"`
+	Page    int                 `json:"page"`
+	Entries []Nep17HistoryEntry `json:"entries"`
+	More    bool                `json:"more"`
+}
+
+// GetNep17History returns a page of the NEP-17 transfers sent and received by walletAddress.
+// The node must have the NEP-17 tracking extension enabled, as public RPC nodes do
+func GetNep17History(ctx context.Context, rpc *RPCClient, walletAddress string, q HistoryQuery) (*Nep17HistoryPage, error) {
+	account, err := StringToUint160(walletAddress)
+	if err != nil {
+		return nil, err
+	}
+	if q.Limit <= 0 {
+		q.Limit = DEFAULT_HISTORY_PAGE_SIZE
+	}
+	if q.Page < 0 {
+		return nil, fmt.Errorf("invalid page %d", q.Page)
+	}
+	// the timestamps are positional before limit and page, so they are always sent
+	start, stop := uint64(0), uint64(time.Now().UnixNano()/int64(time.Millisecond))
+	if !q.From.IsZero() {
+		start = uint64(q.From.UnixNano() / int64(time.Millisecond))
+	}
+	if !q.To.IsZero() {
+		stop = uint64(q.To.UnixNano() / int64(time.Millisecond))
+	}
+
+	var transfers *result.NEP17Transfers
+	err = rpc.Do(ctx, func(cli *client.Client) (err error) {
+		transfers, err = cli.GetNEP17Transfers(account, &start, &stop, &q.Limit, &q.Page)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't get transfers of %s: %w", walletAddress, err)
+	}
+
+	page := &Nep17HistoryPage{
+		Address: walletAddress,
+		Page:    q.Page,
+		Entries: make([]Nep17HistoryEntry, 0, len(transfers.Sent)+len(transfers.Received)),
+		More:    len(transfers.Sent)+len(transfers.Received) >= q.Limit,
+	}
+	for _, side := range []struct {
+		direction TransferDirection
+		transfers []result.NEP17Transfer
+	}{{TRANSFER_SENT, transfers.Sent}, {TRANSFER_RECEIVED, transfers.Received}} {
+		for _, t := range side.transfers {
+			entry, err := historyEntry(ctx, rpc, side.direction, t)
+			if err != nil {
+				return nil, err
+			}
+			page.Entries = append(page.Entries, entry)
+		}
+	}
+	sort.SliceStable(page.Entries, func(i, j int) bool {
+		a, b := page.Entries[i], page.Entries[j]
+		if a.Block != b.Block {
+			return a.Block > b.Block
+		}
+		return a.NotifyIndex > b.NotifyIndex
+	})
+	return page, nil
+}
+
+func historyEntry(ctx context.Context, rpc *RPCClient, direction TransferDirection, t result.NEP17Transfer) (Nep17HistoryEntry, error) {
+	amount, ok := new(big.Int).SetString(t.Amount, 10)
+	if !ok {
+		return Nep17HistoryEntry{}, fmt.Errorf("invalid amount %q in transfer %s", t.Amount, t.TxHash.StringLE())
+	}
+	info, err := rpc.TokenInfo(ctx, t.Asset)
+	if err != nil {
+		return Nep17HistoryEntry{}, err
+	}
+	return Nep17HistoryEntry{
+		TxHash:       t.TxHash,
+		Block:        t.Index,
+		NotifyIndex:  t.NotifyIndex,
+		Time:         time.Unix(0, int64(t.Timestamp)*int64(time.Millisecond)),
+		Direction:    direction,
+		Counterparty: t.Address,
+		Token:        t.Asset,
+		Symbol:       info.Symbol,
+		Amount:       NewAmount(amount, info.Decimals),
+	}, nil
+}
+
+// IterateNep17History calls f for every transfer matching q, newest first, fetching pages as needed from q.Page.
+// It stops early if f returns false
+func IterateNep17History(ctx context.Context, rpc *RPCClient, walletAddress string, q HistoryQuery, f func(Nep17HistoryEntry) bool) error {
+	for {
+		page, err := GetNep17History(ctx, rpc, walletAddress, q)
+		if err != nil {
+			return err
+		}
+		for _, e := range page.Entries {
+			if !f(e) {
+				return nil
+			}
+		}
+		if !page.More {
+			return nil
+		}
+		q.Page++
+	}
+}
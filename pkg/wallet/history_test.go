@@ -0,0 +1,64 @@
+package wallet_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNep17History(t *testing.T) {
+	ctx := context.Background()
+	node := newTestNode(t, netmode.TestNet)
+	gas := util.Uint160{6}
+	other := wallet.Uint160ToString(util.Uint160{9})
+	node.transfers = result.NEP17Transfers{
+		Sent: []result.NEP17Transfer{
+			{Timestamp: 1_600_000_002_000, Asset: gas, Address: other, Amount: "150000000", Index: 12, TxHash: util.Uint256{2}},
+		},
+		Received: []result.NEP17Transfer{
+			{Timestamp: 1_600_000_001_000, Asset: gas, Amount: "5", Index: 10, TxHash: util.Uint256{1}},
+			{Timestamp: 1_600_000_003_000, Asset: gas, Address: other, Amount: "200000000", Index: 13, TxHash: util.Uint256{3}},
+		},
+	}
+	rpc, err := wallet.NewRPCClient(ctx, client.Options{}, wallet.RPC_NETWORK(node.URL))
+	assert.Nil(t, err, "error not nil")
+	address := wallet.Uint160ToString(util.Uint160{1})
+
+	page, err := wallet.GetNep17History(ctx, rpc, address, wallet.HistoryQuery{Limit: 3})
+	assert.Nil(t, err, "error not nil")
+	assert.True(t, page.More, "full page has no next page")
+	assert.Len(t, page.Entries, 3)
+	// the whole history is asked for, not the node's default of the last week
+	assert.Equal(t, float64(0), node.params[1])
+	assert.Equal(t, float64(3), node.params[3])
+
+	newest := page.Entries[0]
+	assert.Equal(t, util.Uint256{3}, newest.TxHash)
+	assert.Equal(t, wallet.TRANSFER_RECEIVED, newest.Direction)
+	assert.Equal(t, other, newest.Counterparty)
+	assert.Equal(t, "GAS", newest.Symbol)
+	assert.Equal(t, int64(8), newest.Amount.Decimals())
+	assert.Equal(t, "2", newest.Amount.String())
+	assert.Equal(t, time.Unix(1_600_000_003, 0), newest.Time)
+	assert.Equal(t, wallet.TRANSFER_SENT, page.Entries[1].Direction)
+	assert.Equal(t, "1.5", page.Entries[1].Amount.String())
+	mint := page.Entries[2]
+	assert.Equal(t, "", mint.Counterparty)
+	assert.Equal(t, "0.00000005", mint.Amount.String())
+
+	var seen int
+	err = wallet.IterateNep17History(ctx, rpc, address, wallet.HistoryQuery{From: time.Unix(1_600_000_000, 0)}, func(e wallet.Nep17HistoryEntry) bool {
+		seen++
+		return true
+	})
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, 3, seen)
+	assert.Equal(t, float64(1_600_000_000_000), node.params[1])
+}
@@ -0,0 +1,203 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+)
+
+var (
+	ErrAccountExists   = errors.New("account already exists in the wallet")
+	ErrAccountNotFound = errors.New("account not found in the wallet")
+	ErrEmptyPassword   = errors.New("password must not be empty")
+)
+
+// ParsePrivateKeyHex parses a 32 byte hex encoded secp256r1 key, with or without a 0x prefix,
+// rejecting anything that is not a valid scalar for the curve
+func ParsePrivateKeyHex(hexString string) (*keys.PrivateKey, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(hexString), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex key: %w", err)
+	}
+	return parsePrivateKeyBytes(b)
+}
+
+func parsePrivateKeyBytes(b []byte) (*keys.PrivateKey, error) {
+	if len(b) != 32 {
+		return nil, fmt.Errorf("private key must be 32 bytes, got %d", len(b))
+	}
+	if !validScalar(b) {
+		return nil, errors.New("private key is out of range for secp256r1")
+	}
+	return keys.NewPrivateKeyFromBytes(b)
+}
+
+// ParsePrivateKeyWIF parses a compressed Neo WIF key
+func ParsePrivateKeyWIF(wif string) (*keys.PrivateKey, error) {
+	w, err := keys.WIFDecode(strings.TrimSpace(wif), keys.WIFVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid WIF: %w", err)
+	}
+	if !w.Compressed {
+		return nil, errors.New("uncompressed WIF keys are not supported")
+	}
+	return parsePrivateKeyBytes(w.PrivateKey.Bytes())
+}
+
+// ParsePrivateKeyNEP2 decrypts a NEP-2 key, checking it against the address hash it carries
+func ParsePrivateKeyNEP2(nep2, passphrase string, scrypt keys.ScryptParams) (*keys.PrivateKey, error) {
+	key, err := keys.NEP2Decrypt(strings.TrimSpace(nep2), passphrase, scrypt)
+	if err != nil {
+		return nil, fmt.Errorf("can't decrypt NEP-2 key: %w", err)
+	}
+	return key, nil
+}
+
+// ImportKey adds key to the wallet encrypted with password, and saves the wallet
+func ImportKey(w *wallet.Wallet, key *keys.PrivateKey, label, password string) (*wallet.Account, error) {
+	if password == "" {
+		return nil, ErrEmptyPassword
+	}
+	acc := wallet.NewAccountFromPrivateKey(key)
+	if w.GetAccount(acc.Contract.ScriptHash()) != nil {
+		return nil, fmt.Errorf("%w: %s", ErrAccountExists, acc.Address)
+	}
+	if err := acc.Encrypt(password, w.Scrypt); err != nil {
+		return nil, err
+	}
+	acc.Label = label
+	w.AddAccount(acc)
+	return acc, w.Save()
+}
+
+// ImportHex adds a hex encoded key to the wallet
+func ImportHex(w *wallet.Wallet, hexKey, label, password string) (*wallet.Account, error) {
+	key, err := ParsePrivateKeyHex(hexKey)
+	if err != nil {
+		return nil, err
+	}
+	return ImportKey(w, key, label, password)
+}
+
+// ImportWIF adds a WIF encoded key to the wallet
+func ImportWIF(w *wallet.Wallet, wif, label, password string) (*wallet.Account, error) {
+	key, err := ParsePrivateKeyWIF(wif)
+	if err != nil {
+		return nil, err
+	}
+	return ImportKey(w, key, label, password)
+}
+
+// ImportNEP2 adds a NEP-2 key to the wallet. It is decrypted with passphrase to check it, then re-encrypted
+// with password under the wallet's scrypt parameters. password may be the same as passphrase
+func ImportNEP2(w *wallet.Wallet, nep2, passphrase, label, password string) (*wallet.Account, error) {
+	key, err := ParsePrivateKeyNEP2(nep2, passphrase, keys.NEP2ScryptParams())
+	if err != nil {
+		return nil, err
+	}
+	return ImportKey(w, key, label, password)
+}
+
+// ExportHex returns the hex encoded private key of the account, decrypting it with password
+func ExportHex(w *wallet.Wallet, address, password string) (string, error) {
+	key, err := unlockedKey(w, address, password)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key.Bytes()), nil
+}
+
+// ExportWIF returns the WIF of the account's private key, decrypting it with password
+func ExportWIF(w *wallet.Wallet, address, password string) (string, error) {
+	key, err := unlockedKey(w, address, password)
+	if err != nil {
+		return "", err
+	}
+	return key.WIF(), nil
+}
+
+// ExportNEP2 returns the account's key encrypted with passphrase using the standard NEP-2 parameters,
+// so any Neo wallet can import it
+func ExportNEP2(w *wallet.Wallet, address, password, passphrase string) (string, error) {
+	if passphrase == "" {
+		return "", ErrEmptyPassword
+	}
+	key, err := unlockedKey(w, address, password)
+	if err != nil {
+		return "", err
+	}
+	return keys.NEP2Encrypt(key, passphrase, keys.NEP2ScryptParams())
+}
+
+func findAccount(w *wallet.Wallet, address string) (*wallet.Account, error) {
+	for _, acc := range w.Accounts {
+		if acc.Address == address {
+			return acc, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, address)
+}
+
+func unlockedKey(w *wallet.Wallet, address, password string) (*keys.PrivateKey, error) {
+	acc, err := findAccount(w, address)
+	if err != nil {
+		return nil, err
+	}
+	if err := acc.Decrypt(password, w.Scrypt); err != nil {
+		return nil, fmt.Errorf("can't decrypt %s: %w", address, err)
+	}
+	return acc.PrivateKey(), nil
+}
+
+// RemoveAccount removes the account from the wallet and saves it
+func RemoveAccount(w *wallet.Wallet, address string) error {
+	if err := w.RemoveAccount(address); err != nil {
+		return fmt.Errorf("%w: %s", ErrAccountNotFound, address)
+	}
+	return w.Save()
+}
+
+// RenameAccount changes the account's label and saves the wallet
+func RenameAccount(w *wallet.Wallet, address, label string) error {
+	acc, err := findAccount(w, address)
+	if err != nil {
+		return err
+	}
+	acc.Label = label
+	return w.Save()
+}
+
+// ChangePassword re-encrypts the account's key with newPassword and saves the wallet
+func ChangePassword(w *wallet.Wallet, address, oldPassword, newPassword string) error {
+	if newPassword == "" {
+		return ErrEmptyPassword
+	}
+	acc, err := findAccount(w, address)
+	if err != nil {
+		return err
+	}
+	if err := acc.Decrypt(oldPassword, w.Scrypt); err != nil {
+		return fmt.Errorf("can't decrypt %s: %w", address, err)
+	}
+	if err := acc.Encrypt(newPassword, w.Scrypt); err != nil {
+		return err
+	}
+	return w.Save()
+}
+
+// SetDefaultAccount marks the account as the wallet default, clearing the flag on every other account
+func SetDefaultAccount(w *wallet.Wallet, address string) error {
+	acc, err := findAccount(w, address)
+	if err != nil {
+		return err
+	}
+	for _, a := range w.Accounts {
+		a.Default = false
+	}
+	acc.Default = true
+	return w.Save()
+}
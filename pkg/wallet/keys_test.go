@@ -0,0 +1,114 @@
+package wallet_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	wallet2 "github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePrivateKey(t *testing.T) {
+	key, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+
+	parsed, err := wallet2.ParsePrivateKeyHex("0x" + key.String())
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, key.Bytes(), parsed.Bytes())
+
+	ecdsaKey, err := wallet2.PrivateKeyFromHexString(key.String())
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, key.PrivateKey.D, ecdsaKey.D)
+
+	parsed, err = wallet2.ParsePrivateKeyWIF(key.WIF())
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, key.Bytes(), parsed.Bytes())
+
+	for _, bad := range []string{
+		"",
+		"zz",
+		"0102",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"ffffffff00000000ffffffffffffffffbce6faada7179e84f3b9cac2fc632551",
+	} {
+		_, err := wallet2.PrivateKeyFromHexString(bad)
+		assert.NotNil(t, err, "accepted %q", bad)
+	}
+	_, err = wallet2.ParsePrivateKeyWIF("not a wif")
+	assert.NotNil(t, err, "accepted invalid WIF")
+}
+
+func TestImportExport(t *testing.T) {
+	w, err := wallet.NewWallet(filepath.Join(t.TempDir(), "wallet.json"))
+	assert.Nil(t, err, "error not nil")
+
+	key, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	acc, err := wallet2.ImportHex(w, key.String(), "hex", "password")
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, key.Address(), acc.Address)
+
+	_, err = wallet2.ImportWIF(w, key.WIF(), "again", "password")
+	assert.True(t, errors.Is(err, wallet2.ErrAccountExists))
+
+	wif, err := wallet2.ExportWIF(w, acc.Address, "password")
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, key.WIF(), wif)
+	_, err = wallet2.ExportHex(w, acc.Address, "wrong")
+	assert.NotNil(t, err, "exported with the wrong password")
+
+	nep2, err := wallet2.ExportNEP2(w, acc.Address, "password", "passphrase")
+	assert.Nil(t, err, "error not nil")
+	assert.Nil(t, wallet2.RemoveAccount(w, acc.Address), "error not nil")
+	assert.Nil(t, w.GetAccount(acc.Contract.ScriptHash()))
+
+	_, err = wallet2.ImportNEP2(w, nep2, "wrong", "nep2", "password")
+	assert.NotNil(t, err, "imported with the wrong passphrase")
+	acc, err = wallet2.ImportNEP2(w, nep2, "passphrase", "nep2", "password")
+	assert.Nil(t, err, "error not nil")
+	h, err := wallet2.ExportHex(w, acc.Address, "password")
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, key.String(), h)
+
+	reloaded, err := wallet.NewWalletFromFile(w.Path())
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, 1, len(reloaded.Accounts))
+	assert.Equal(t, "nep2", reloaded.Accounts[0].Label)
+}
+
+func TestAccountManagement(t *testing.T) {
+	w, err := wallet.NewWallet(filepath.Join(t.TempDir(), "wallet.json"))
+	assert.Nil(t, err, "error not nil")
+
+	var addresses []string
+	for i := 0; i < 2; i++ {
+		key, err := keys.NewPrivateKey()
+		assert.Nil(t, err, "error not nil")
+		acc, err := wallet2.ImportKey(w, key, "", "password")
+		assert.Nil(t, err, "error not nil")
+		addresses = append(addresses, acc.Address)
+	}
+	_, err = wallet2.ImportKey(w, nil, "", "")
+	assert.True(t, errors.Is(err, wallet2.ErrEmptyPassword))
+
+	assert.Nil(t, wallet2.RenameAccount(w, addresses[0], "main"), "error not nil")
+	assert.Nil(t, wallet2.SetDefaultAccount(w, addresses[1]), "error not nil")
+	assert.Nil(t, wallet2.SetDefaultAccount(w, addresses[0]), "error not nil")
+	assert.True(t, errors.Is(wallet2.SetDefaultAccount(w, "NXcncJT8jipH7ZkaUQzkb6Dx28w7D1Njd3"), wallet2.ErrAccountNotFound))
+
+	assert.NotNil(t, wallet2.ChangePassword(w, addresses[0], "wrong", "new"), "changed with the wrong password")
+	assert.Nil(t, wallet2.ChangePassword(w, addresses[0], "password", "new"), "error not nil")
+
+	reloaded, err := wallet.NewWalletFromFile(w.Path())
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, "main", reloaded.Accounts[0].Label)
+	assert.True(t, reloaded.Accounts[0].Default)
+	assert.False(t, reloaded.Accounts[1].Default)
+	_, err = wallet2.ExportHex(reloaded, addresses[0], "new")
+	assert.Nil(t, err, "error not nil")
+	_, err = wallet2.ExportHex(reloaded, addresses[0], "password")
+	assert.NotNil(t, err, "old password still works")
+}
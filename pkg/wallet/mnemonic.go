@@ -0,0 +1,238 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/tyler-smith/go-bip39"
+)
+
+const (
+	// NEO_COIN_TYPE is the SLIP-44 coin type registered for Neo
+	NEO_COIN_TYPE = 888
+	// HARDENED_OFFSET is added to an index to derive a hardened child
+	HARDENED_OFFSET uint32 = 0x80000000
+	// DEFAULT_DERIVATION_PATH is the BIP-44 path of the first Neo account
+	DEFAULT_DERIVATION_PATH = "m/44'/888'/0'/0/0"
+	// DEFAULT_MNEMONIC_BITS gives a 24 word mnemonic
+	DEFAULT_MNEMONIC_BITS = 256
+)
+
+// nist256p1Seed is the SLIP-10 HMAC key for secp256r1 master keys
+var nist256p1Seed = []byte("Nist256p1 seed")
+
+var ErrInvalidMnemonic = errors.New("invalid mnemonic")
+
+// NewMnemonic generates a BIP-39 mnemonic from bits of entropy, a multiple of 32 between 128 and 256
+func NewMnemonic(bits int) (string, error) {
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// ValidateMnemonic checks the words are in the English list and the checksum matches
+func ValidateMnemonic(mnemonic string) error {
+	if _, err := bip39.EntropyFromMnemonic(normaliseMnemonic(mnemonic)); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidMnemonic, err)
+	}
+	return nil
+}
+
+// SeedFromMnemonic returns the 64 byte BIP-39 seed. passphrase is the optional extra word, not a wallet password
+func SeedFromMnemonic(mnemonic, passphrase string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	return bip39.NewSeed(normaliseMnemonic(mnemonic), passphrase), nil
+}
+
+func normaliseMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+}
+
+// ExtendedKey is a secp256r1 private key with the chain code needed to derive its children, following SLIP-10
+type ExtendedKey struct {
+	key       []byte
+	chainCode []byte
+	Depth     uint8
+	Index     uint32
+}
+
+// NewMasterKey derives the root key from a BIP-39 seed
+func NewMasterKey(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("seed must be between 16 and 64 bytes, got %d", len(seed))
+	}
+	data := seed
+	for {
+		mac := hmac.New(sha512.New, nist256p1Seed)
+		mac.Write(data)
+		i := mac.Sum(nil)
+		if validScalar(i[:32]) {
+			return &ExtendedKey{key: i[:32], chainCode: i[32:]}, nil
+		}
+		data = i
+	}
+}
+
+// Child derives the child at index. Indices from HARDENED_OFFSET up are hardened
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	if k.Depth == 255 {
+		return nil, errors.New("maximum derivation depth reached")
+	}
+	var data []byte
+	if index >= HARDENED_OFFSET {
+		data = append([]byte{0}, k.key...)
+	} else {
+		data = k.publicKey().Bytes()
+	}
+	data = append(data, ser32(index)...)
+
+	n := elliptic.P256().Params().N
+	for {
+		mac := hmac.New(sha512.New, k.chainCode)
+		mac.Write(data)
+		i := mac.Sum(nil)
+		il := new(big.Int).SetBytes(i[:32])
+		if il.Cmp(n) < 0 {
+			child := il.Add(il, new(big.Int).SetBytes(k.key))
+			child.Mod(child, n)
+			if child.Sign() != 0 {
+				return &ExtendedKey{
+					key:       child.FillBytes(make([]byte, 32)),
+					chainCode: i[32:],
+					Depth:     k.Depth + 1,
+					Index:     index,
+				}, nil
+			}
+		}
+		data = append(append([]byte{1}, i[32:]...), ser32(index)...)
+	}
+}
+
+// Derive follows a path such as DEFAULT_DERIVATION_PATH from this key
+func (k *ExtendedKey) Derive(path string) (*ExtendedKey, error) {
+	indices, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	key := k
+	for _, i := range indices {
+		if key, err = key.Child(i); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// ChainCode returns a copy of the key's chain code
+func (k *ExtendedKey) ChainCode() []byte {
+	return append([]byte(nil), k.chainCode...)
+}
+
+// PrivateKey returns the key for use with GetWalletFromPrivateKey, OwnerIDFromPrivateKey and the signers
+func (k *ExtendedKey) PrivateKey() *ecdsa.PrivateKey {
+	return &k.neoKey().PrivateKey
+}
+
+func (k *ExtendedKey) neoKey() *keys.PrivateKey {
+	key, _ := keys.NewPrivateKeyFromBytes(k.key)
+	return key
+}
+
+func (k *ExtendedKey) publicKey() *keys.PublicKey {
+	return k.neoKey().PublicKey()
+}
+
+// ParseDerivationPath parses paths like m/44'/888'/0'/0/0. A trailing ' or h marks a hardened index
+func ParseDerivationPath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if parts[0] != "m" {
+		return nil, fmt.Errorf("derivation path %q must start with m", path)
+	}
+	indices := make([]uint32, 0, len(parts)-1)
+	for _, p := range parts[1:] {
+		hardened := strings.HasSuffix(p, "'") || strings.HasSuffix(p, "h") || strings.HasSuffix(p, "H")
+		if hardened {
+			p = p[:len(p)-1]
+		}
+		i, err := strconv.ParseUint(p, 10, 32)
+		if err != nil || uint32(i) >= HARDENED_OFFSET {
+			return nil, fmt.Errorf("invalid index %q in derivation path %q", p, path)
+		}
+		if hardened {
+			i += uint64(HARDENED_OFFSET)
+		}
+		indices = append(indices, uint32(i))
+	}
+	return indices, nil
+}
+
+// NeoDerivationPath is the BIP-44 path of the index'th address of account
+func NeoDerivationPath(account, index uint32) string {
+	return fmt.Sprintf("m/44'/%d'/%d'/0/%d", NEO_COIN_TYPE, account, index)
+}
+
+// PrivateKeyFromMnemonic derives the key at path from a mnemonic and optional passphrase
+func PrivateKeyFromMnemonic(mnemonic, passphrase, path string) (*ecdsa.PrivateKey, error) {
+	seed, err := SeedFromMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	key, err := master.Derive(path)
+	if err != nil {
+		return nil, err
+	}
+	return key.PrivateKey(), nil
+}
+
+// AccountsFromMnemonic derives count consecutive addresses of account, labelled with their derivation path
+func AccountsFromMnemonic(mnemonic, passphrase string, account, count uint32) ([]*wallet.Account, error) {
+	seed, err := SeedFromMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]*wallet.Account, 0, count)
+	for i := uint32(0); i < count; i++ {
+		path := NeoDerivationPath(account, i)
+		key, err := master.Derive(path)
+		if err != nil {
+			return nil, err
+		}
+		acc := GetWalletFromPrivateKey(key.PrivateKey())
+		acc.Label = path
+		accounts = append(accounts, acc)
+	}
+	return accounts, nil
+}
+
+func ser32(i uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, i)
+	return b
+}
+
+func validScalar(b []byte) bool {
+	d := new(big.Int).SetBytes(b)
+	return d.Sign() != 0 && d.Cmp(elliptic.P256().Params().N) < 0
+}
@@ -0,0 +1,80 @@
+package wallet_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	wallet2 "github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/stretchr/testify/assert"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// SLIP-10 test vector 1 for nist256p1
+func TestDeriveSLIP10(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := wallet2.NewMasterKey(seed)
+	assert.Nil(t, err, "error not nil")
+
+	vectors := []struct {
+		path, chainCode, privateKey, publicKey string
+	}{
+		{"m", "beeb672fe4621673f722f38529c07392fecaa61015c80c34f29ce8b41b3cb6ea", "612091aaa12e22dd2abef664f8a01a82cae99ad7441b7ef8110424915c268bc2", "0266874dc6ade47b3ecd096745ca09bcd29638dd52c2c12117b11ed3e458cfa9e8"},
+		{"m/0'", "3460cea53e6a6bb5fb391eeef3237ffd8724bf0a40e94943c98b83825342ee11", "6939694369114c67917a182c59ddb8cafc3004e63ca5d3b84403ba8613debc0c", "0384610f5ecffe8fda089363a41f56a5c7ffc1d81b59a612d0d649b2d22355590c"},
+		{"m/0'/1", "4187afff1aafa8445010097fb99d23aee9f599450c7bd140b6826ac22ba21d0c", "284e9d38d07d21e4e281b645089a94f4cf5a5a81369acf151a1c3a57f18b2129", "03526c63f8d0b4bbbf9c80df553fe66742df4676b241dabefdef67733e070f6844"},
+		{"m/0h/1/2h", "98c7514f562e64e74170cc3cf304ee1ce54d6b6da4f880f313e8204c2a185318", "694596e8a54f252c960eb771a3c41e7e32496d03b954aeb90f61635b8e092aa7", "0359cf160040778a4b14c5f4d7b76e327ccc8c4a6086dd9451b7482b5a4972dda0"},
+	}
+	for _, v := range vectors {
+		k, err := master.Derive(v.path)
+		assert.Nil(t, err, "error not nil")
+		key := keys.PrivateKey{PrivateKey: *k.PrivateKey()}
+		assert.Equal(t, v.chainCode, hex.EncodeToString(k.ChainCode()), v.path)
+		assert.Equal(t, v.privateKey, key.String(), v.path)
+		assert.Equal(t, v.publicKey, hex.EncodeToString(key.PublicKey().Bytes()), v.path)
+	}
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	indices, err := wallet2.ParseDerivationPath(wallet2.DEFAULT_DERIVATION_PATH)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, []uint32{44 + wallet2.HARDENED_OFFSET, 888 + wallet2.HARDENED_OFFSET, wallet2.HARDENED_OFFSET, 0, 0}, indices)
+	assert.Equal(t, wallet2.DEFAULT_DERIVATION_PATH, wallet2.NeoDerivationPath(0, 0))
+
+	for _, bad := range []string{"", "44'/888'", "m/x", "m/-1", "m/2147483648", "m//0"} {
+		_, err := wallet2.ParseDerivationPath(bad)
+		assert.NotNil(t, err, "accepted %q", bad)
+	}
+}
+
+func TestMnemonic(t *testing.T) {
+	mnemonic, err := wallet2.NewMnemonic(wallet2.DEFAULT_MNEMONIC_BITS)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, 24, len(strings.Fields(mnemonic)))
+	assert.Nil(t, wallet2.ValidateMnemonic(mnemonic), "error not nil")
+
+	err = wallet2.ValidateMnemonic(strings.Replace(testMnemonic, "about", "abandon", 1))
+	assert.True(t, errors.Is(err, wallet2.ErrInvalidMnemonic))
+	_, err = wallet2.NewMnemonic(100)
+	assert.NotNil(t, err, "accepted 100 bits of entropy")
+
+	key, err := wallet2.PrivateKeyFromMnemonic("  Abandon "+testMnemonic[8:], "", wallet2.DEFAULT_DERIVATION_PATH)
+	assert.Nil(t, err, "error not nil")
+	accounts, err := wallet2.AccountsFromMnemonic(testMnemonic, "", 0, 3)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, 3, len(accounts))
+	assert.Equal(t, "NYqCjmV8g8PFCYpyD3K4kSCkQxZff1UNMV", accounts[0].Address)
+	assert.Equal(t, wallet2.GetWalletFromPrivateKey(key).Address, accounts[0].Address)
+	assert.Equal(t, wallet2.NeoDerivationPath(0, 2), accounts[2].Label)
+	assert.NotEqual(t, accounts[0].Address, accounts[1].Address)
+
+	id, err := wallet2.OwnerIDFromPrivateKey(key)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, accounts[0].Address, id.String())
+
+	withPassphrase, err := wallet2.AccountsFromMnemonic(testMnemonic, "TREZOR", 0, 1)
+	assert.Nil(t, err, "error not nil")
+	assert.NotEqual(t, accounts[0].Address, withPassphrase[0].Address)
+}
@@ -0,0 +1,243 @@
+package wallet
+
+import (
+	"context"
+	"crypto/elliptic"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/configwizard/gaspump-api/pkg/signer"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	sccontext "github.com/nspcc-dev/neo-go/pkg/smartcontract/context"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+)
+
+// MULTISIG_CONTEXT_TYPE is the verifiable type neo-go uses for transaction signing contexts
+const MULTISIG_CONTEXT_TYPE = "Neo.Network.P2P.Payloads.Transaction"
+
+// NewMultiSigAccount creates a watch-only m-of-n multisig account. The order of publicKeys doesn't matter
+func NewMultiSigAccount(m int, publicKeys []*keys.PublicKey) (*wallet.Account, error) {
+	script, err := smartcontract.CreateMultiSigRedeemScript(m, copyKeys(publicKeys))
+	if err != nil {
+		return nil, err
+	}
+	params := make([]wallet.ContractParam, m)
+	for i := range params {
+		params[i].Name = fmt.Sprintf("parameter%d", i)
+		params[i].Type = smartcontract.SignatureType
+	}
+	return &wallet.Account{
+		Address: address.Uint160ToString(hash.Hash160(script)),
+		Contract: &wallet.Contract{
+			Script:     script,
+			Parameters: params,
+		},
+		Label: fmt.Sprintf("%d-of-%d multisig", m, len(publicKeys)),
+	}, nil
+}
+
+// copyKeys stops neo-go sorting the caller's slice when it builds the script
+func copyKeys(publicKeys []*keys.PublicKey) keys.PublicKeys {
+	c := make(keys.PublicKeys, len(publicKeys))
+	copy(c, publicKeys)
+	return c
+}
+
+// GenerateMultiSignWalletFromSigners adds an m-of-n multisig account to the wallet at path, creating the file if needed.
+// If participant is given it must be unlocked and one of publicKeys; its encrypted key is stored with the multisig
+// account so this wallet can sign for it later, the way neo-go does. Otherwise the account is watch-only
+func GenerateMultiSignWalletFromSigners(path string, m int, publicKeys []*keys.PublicKey, participant *wallet.Account) (*wallet.Wallet, *wallet.Account, error) {
+	var acc *wallet.Account
+	if participant != nil {
+		if participant.PrivateKey() == nil {
+			return nil, nil, errors.New("participant account is locked")
+		}
+		multisig := *participant
+		if err := multisig.ConvertMultisig(m, copyKeys(publicKeys)); err != nil {
+			return nil, nil, err
+		}
+		multisig.Label = fmt.Sprintf("%d-of-%d multisig", m, len(publicKeys))
+		multisig.Default = false
+		acc = &multisig
+	} else {
+		var err error
+		if acc, err = NewMultiSigAccount(m, publicKeys); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var w *wallet.Wallet
+	var err error
+	if _, statErr := os.Stat(path); statErr == nil {
+		w, err = wallet.NewWalletFromFile(path)
+	} else {
+		w, err = wallet.NewWallet(path)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if w.GetAccount(acc.Contract.ScriptHash()) != nil {
+		return w, nil, fmt.Errorf("wallet already contains %s", acc.Address)
+	}
+	w.AddAccount(acc)
+	if err := w.Save(); err != nil {
+		return nil, nil, err
+	}
+	return w, acc, nil
+}
+
+// MultiSigParticipants returns the threshold and public keys of a multisig account
+func MultiSigParticipants(multisig *wallet.Account) (int, keys.PublicKeys, error) {
+	if multisig.Contract == nil {
+		return 0, nil, errors.New("account has no contract")
+	}
+	m, pubs, ok := vm.ParseMultiSigContract(multisig.Contract.Script)
+	if !ok {
+		return 0, nil, fmt.Errorf("%s is not a multisig account", multisig.Address)
+	}
+	publicKeys := make(keys.PublicKeys, len(pubs))
+	for i := range pubs {
+		pub, err := keys.NewPublicKeyFromBytes(pubs[i], elliptic.P256())
+		if err != nil {
+			return 0, nil, err
+		}
+		publicKeys[i] = pub
+	}
+	return m, publicKeys, nil
+}
+
+// CreateMultiSigTransaction builds a transaction running script with the multisig account as sender and returns
+// a signing context for it. The context can be saved with SaveMultiSigContext and passed between participants.
+// A sysFee below 0 is estimated by a test invocation
+func CreateMultiSigTransaction(ctx context.Context, rpc *RPCClient, script []byte, multisig *wallet.Account, sysFee, netFee int64) (*sccontext.ParameterContext, error) {
+	if _, _, err := MultiSigParticipants(multisig); err != nil {
+		return nil, err
+	}
+	var tx *transaction.Transaction
+	err := rpc.Do(ctx, func(cli *client.Client) (err error) {
+		tx, err = cli.CreateTxFromScript(script, multisig, sysFee, netFee, nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sccontext.NewParameterContext(MULTISIG_CONTEXT_TYPE, rpc.Network(), tx), nil
+}
+
+// SignedData is what a witness signature covers: the network magic followed by the transaction hash
+func SignedData(network netmode.Magic, tx *transaction.Transaction) []byte {
+	data := make([]byte, 4+util.Uint256Size)
+	binary.LittleEndian.PutUint32(data, uint32(network))
+	h := tx.Hash()
+	copy(data[4:], h[:])
+	return data
+}
+
+// SignMultiSigContext adds participant's signature to the context
+func SignMultiSigContext(pc *sccontext.ParameterContext, multisig *wallet.Account, participant *wallet.Account) error {
+	s, err := signer.NewAccountSigner(participant)
+	if err != nil {
+		return err
+	}
+	return SignMultiSigContextWith(context.Background(), pc, multisig, s)
+}
+
+// SignMultiSigContextWith adds a signature made by s, which may be a remote signer, to the context
+func SignMultiSigContextWith(ctx context.Context, pc *sccontext.ParameterContext, multisig *wallet.Account, s signer.Signer) error {
+	tx, ok := pc.Verifiable.(*transaction.Transaction)
+	if !ok {
+		return errors.New("context does not hold a transaction")
+	}
+	sig, err := s.Sign(signer.WithPayloadType(ctx, signer.PayloadTransaction), signer.SchemeRFC6979, SignedData(pc.Network, tx))
+	if err != nil {
+		return err
+	}
+	return pc.AddSignature(multisig.Contract.ScriptHash(), multisig.Contract, s.PublicKey(), sig)
+}
+
+// MultiSigSignatures returns how many signatures the context holds for the multisig account and how many it needs
+func MultiSigSignatures(pc *sccontext.ParameterContext, multisig *wallet.Account) (int, int, error) {
+	m, _, err := MultiSigParticipants(multisig)
+	if err != nil {
+		return 0, 0, err
+	}
+	item, ok := pc.Items[multisig.Contract.ScriptHash()]
+	if !ok {
+		return 0, m, nil
+	}
+	return len(item.Signatures), m, nil
+}
+
+// SaveMultiSigContext writes the context as JSON, in the same format as neo-go's context files
+func SaveMultiSigContext(pc *sccontext.ParameterContext, path string) error {
+	data, err := json.Marshal(pc)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// LoadMultiSigContext reads a context written by SaveMultiSigContext or neo-go
+func LoadMultiSigContext(path string) (*sccontext.ParameterContext, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pc := new(sccontext.ParameterContext)
+	if err := json.Unmarshal(data, pc); err != nil {
+		return nil, fmt.Errorf("can't read signing context: %w", err)
+	}
+	return pc, nil
+}
+
+// CompleteMultiSigTransaction attaches the multisig witness once enough participants have signed
+func CompleteMultiSigTransaction(pc *sccontext.ParameterContext, multisig *wallet.Account) (*transaction.Transaction, error) {
+	have, need, err := MultiSigSignatures(pc, multisig)
+	if err != nil {
+		return nil, err
+	}
+	if have < need {
+		return nil, fmt.Errorf("%d of %d signatures collected", have, need)
+	}
+	tx, ok := pc.Verifiable.(*transaction.Transaction)
+	if !ok {
+		return nil, errors.New("context does not hold a transaction")
+	}
+	witness, err := pc.GetWitness(multisig.Contract.ScriptHash())
+	if err != nil {
+		return nil, err
+	}
+	for i := range tx.Signers {
+		if tx.Signers[i].Account.Equals(multisig.Contract.ScriptHash()) {
+			if len(tx.Scripts) != len(tx.Signers) {
+				tx.Scripts = make([]transaction.Witness, len(tx.Signers))
+			}
+			tx.Scripts[i] = *witness
+			return tx, nil
+		}
+	}
+	return nil, fmt.Errorf("%s is not a signer of the transaction", multisig.Address)
+}
+
+// SendMultiSigTransaction completes the transaction and sends it
+func SendMultiSigTransaction(ctx context.Context, rpc *RPCClient, pc *sccontext.ParameterContext, multisig *wallet.Account) (util.Uint256, error) {
+	tx, err := CompleteMultiSigTransaction(pc, multisig)
+	if err != nil {
+		return util.Uint256{}, err
+	}
+	if err := rpc.SendTx(ctx, tx); err != nil {
+		return util.Uint256{}, err
+	}
+	return tx.Hash(), nil
+}
@@ -0,0 +1,66 @@
+package wallet_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	sccontext "github.com/nspcc-dev/neo-go/pkg/smartcontract/context"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	neowallet "github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiSig(t *testing.T) {
+	dir := t.TempDir()
+	var participants []*neowallet.Account
+	var publicKeys []*keys.PublicKey
+	for i := 0; i < 3; i++ {
+		key, err := keys.NewPrivateKey()
+		assert.Nil(t, err, "error not nil")
+		participants = append(participants, neowallet.NewAccountFromPrivateKey(key))
+		publicKeys = append(publicKeys, key.PublicKey())
+	}
+
+	w, multisig, err := wallet.GenerateMultiSignWalletFromSigners(filepath.Join(dir, "wallet.json"), 2, publicKeys, participants[0])
+	assert.Nil(t, err, "error not nil")
+	assert.Len(t, w.Accounts, 1)
+	watchOnly, err := wallet.NewMultiSigAccount(2, []*keys.PublicKey{publicKeys[2], publicKeys[1], publicKeys[0]})
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, multisig.Address, watchOnly.Address, "key order changed the address")
+	m, pubs, err := wallet.MultiSigParticipants(watchOnly)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, 2, m)
+	assert.Len(t, pubs, 3)
+
+	tx := transaction.New([]byte{byte(opcode.PUSH1)}, 0)
+	tx.Signers = []transaction.Signer{{Account: multisig.Contract.ScriptHash(), Scopes: transaction.CalledByEntry}}
+	pc := sccontext.NewParameterContext(wallet.MULTISIG_CONTEXT_TYPE, netmode.TestNet, tx)
+
+	assert.Nil(t, wallet.SignMultiSigContext(pc, watchOnly, participants[0]), "error not nil")
+	_, err = wallet.CompleteMultiSigTransaction(pc, watchOnly)
+	assert.NotNil(t, err, "completed below the threshold")
+
+	// the context travels to the next participant as a file
+	path := filepath.Join(dir, "context.json")
+	assert.Nil(t, wallet.SaveMultiSigContext(pc, path), "error not nil")
+	pc, err = wallet.LoadMultiSigContext(path)
+	assert.Nil(t, err, "error not nil")
+	assert.Nil(t, wallet.SignMultiSigContext(pc, watchOnly, participants[2]), "error not nil")
+	have, need, err := wallet.MultiSigSignatures(pc, watchOnly)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, 2, have)
+	assert.Equal(t, 2, need)
+
+	signed, err := wallet.CompleteMultiSigTransaction(pc, watchOnly)
+	assert.Nil(t, err, "error not nil")
+	assert.Len(t, signed.Scripts, 1)
+	assert.Equal(t, multisig.Contract.Script, signed.Scripts[0].VerificationScript)
+	digest := hash.NetSha256(uint32(netmode.TestNet), signed)
+	sig := pc.Items[multisig.Contract.ScriptHash()].GetSignature(publicKeys[2])
+	assert.True(t, publicKeys[2].Verify(sig, digest.BytesBE()), "signature invalid")
+}
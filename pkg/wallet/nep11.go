@@ -0,0 +1,291 @@
+package wallet
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/nspcc-dev/neofs-sdk-go/object/address"
+)
+
+const (
+	// HTTP_GATEWAY_TESTNET and HTTP_GATEWAY_MAINNET serve NeoFS objects over HTTP at <gateway>/<container>/<object>
+	HTTP_GATEWAY_TESTNET = "https://http.testnet.fs.neo.org"
+	HTTP_GATEWAY_MAINNET = "https://http.fs.neo.org"
+	// NEOFS_URI_SCHEME prefixes a NeoFS object address, e.g. neofs:<container>/<object>
+	NEOFS_URI_SCHEME = "neofs:"
+
+	// NFT_PROPERTY_NEOFS is the NFT property holding the NeoFS address of the token's media,
+	// alongside the well known name, description, image and tokenURI properties
+	NFT_PROPERTY_NEOFS = "neofs"
+)
+
+// TokenID is a NEP-11 token id. Ids are byte strings, they are shown and marshalled as hex
+type TokenID []byte
+
+// ParseTokenID parses a hex token id, as returned by getnep11balances
+func ParseTokenID(s string) (TokenID, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token id %q: %w", s, err)
+	}
+	return b, nil
+}
+
+func (id TokenID) String() string {
+	return hex.EncodeToString(id)
+}
+
+func (id TokenID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+func (id *TokenID) UnmarshalText(text []byte) error {
+	b, err := ParseTokenID(string(text))
+	if err != nil {
+		return err
+	}
+	*id = b
+	return nil
+}
+
+// Nep11Token is an NFT, or a share of a divisible one, held by an address
+type Nep11Token struct {
+	Asset       util.Uint160 `json:"asset"`
+	Symbol      string       `json:"symbol"`
+	ID          TokenID      `json:"id"`
+	Amount      Amount       `json:"amount"`
+	Divisible   bool         `json:"divisible"`
+	LastUpdated uint32       `json:"lastUpdated"`
+}
+
+// GetNep11Tokens lists the NFTs held by walletAddress. The node must have the NEP-11 tracking extension enabled
+func GetNep11Tokens(ctx context.Context, rpc *RPCClient, walletAddress string) ([]Nep11Token, error) {
+	owner, err := StringToUint160(walletAddress)
+	if err != nil {
+		return nil, err
+	}
+	var balances *result.NEP11Balances
+	err = rpc.Do(ctx, func(cli *client.Client) (err error) {
+		balances, err = cli.GetNEP11Balances(owner)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't get NEP-11 balances of %s: %w", walletAddress, err)
+	}
+	var tokens []Nep11Token
+	for _, asset := range balances.Balances {
+		info, err := rpc.TokenInfo(ctx, asset.Asset)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range asset.Tokens {
+			id, err := ParseTokenID(t.ID)
+			if err != nil {
+				return nil, err
+			}
+			amount, ok := new(big.Int).SetString(t.Amount, 10)
+			if !ok {
+				return nil, fmt.Errorf("invalid amount %q of token %s", t.Amount, t.ID)
+			}
+			tokens = append(tokens, Nep11Token{
+				Asset:       asset.Asset,
+				Symbol:      info.Symbol,
+				ID:          id,
+				Amount:      NewAmount(amount, info.Decimals),
+				Divisible:   info.Decimals > 0,
+				LastUpdated: t.LastUpdated,
+			})
+		}
+	}
+	return tokens, nil
+}
+
+// Nep11Properties are the properties of an NFT. Byte string values that are valid UTF-8 are decoded to strings,
+// integers to *big.Int, anything else is left as the stack item's value
+type Nep11Properties map[string]interface{}
+
+// String returns a text property such as name or image, or "" if it is missing or not text
+func (p Nep11Properties) String(key string) string {
+	s, _ := p[key].(string)
+	return s
+}
+
+// NeoFSAddress returns the NeoFS object an NFT's media is stored in. It looks for the neofs property first,
+// then for a neofs: URI or gateway URL in image and tokenURI
+func (p Nep11Properties) NeoFSAddress() (*address.Address, error) {
+	for _, key := range []string{NFT_PROPERTY_NEOFS, "image", "tokenURI"} {
+		if addr, err := ParseNeoFSLocation(p.String(key)); err == nil {
+			return addr, nil
+		}
+	}
+	return nil, errors.New("NFT has no NeoFS object")
+}
+
+// GetNep11Properties calls the optional properties method of an NFT contract for a token
+func GetNep11Properties(ctx context.Context, rpc *RPCClient, token util.Uint160, id TokenID) (Nep11Properties, error) {
+	// the id is sent as bytes, client.NEP11Properties sends it as a string which breaks ids that aren't UTF-8
+	var inv *result.Invoke
+	err := rpc.Do(ctx, func(cli *client.Client) (err error) {
+		inv, err = cli.InvokeFunction(token, "properties", []smartcontract.Parameter{{
+			Type:  smartcontract.ByteArrayType,
+			Value: []byte(id),
+		}}, nil)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't get properties of token %s: %w", id, err)
+	}
+	item, err := (&InvokeResult{inv}).Item(0)
+	if err != nil {
+		return nil, fmt.Errorf("can't get properties of token %s: %w", id, err)
+	}
+	m, ok := item.(*stackitem.Map)
+	if !ok {
+		return nil, fmt.Errorf("properties of token %s are a %s, not a map", id, item.Type())
+	}
+	props := Nep11Properties{}
+	for _, e := range m.Value().([]stackitem.MapElement) {
+		key, err := e.Key.TryBytes()
+		if err != nil {
+			return nil, fmt.Errorf("invalid property key: %w", err)
+		}
+		switch v := e.Value.(type) {
+		case *stackitem.ByteArray, *stackitem.Buffer:
+			b, _ := v.TryBytes()
+			if utf8.Valid(b) {
+				props[string(key)] = string(b)
+			} else {
+				props[string(key)] = b
+			}
+		case *stackitem.BigInteger:
+			props[string(key)] = v.Value().(*big.Int)
+		default:
+			props[string(key)] = e.Value.Value()
+		}
+	}
+	return props, nil
+}
+
+// TransferNep11 sends an NFT to walletTo and returns the transaction hash. For a non-divisible token amount must be 1,
+// for a divisible one it is the share to send, converted to the token's decimals. data is passed to onNEP11Payment
+// of a receiving contract
+func TransferNep11(ctx context.Context, rpc *RPCClient, a *wallet.Account, amount Amount, walletTo string, token util.Uint160, id TokenID, data interface{}) (string, error) {
+	recipient, err := StringToUint160(walletTo)
+	if err != nil {
+		return "", err
+	}
+	from, err := StringToUint160(a.Address)
+	if err != nil {
+		return "", err
+	}
+	info, err := rpc.TokenInfo(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	if info.Standard != manifest.NEP11StandardName {
+		return "", fmt.Errorf("%s is not a NEP-11 token", info.Symbol)
+	}
+	amount, err = amount.Rescale(info.Decimals)
+	if err != nil {
+		return "", err
+	}
+	if amount.Sign() <= 0 {
+		return "", errors.New("amount must be positive")
+	}
+	divisible := info.Decimals > 0
+	if !divisible && amount.Value().Cmp(big.NewInt(1)) != 0 {
+		return "", fmt.Errorf("%s is not divisible, its tokens can only be sent whole", info.Symbol)
+	}
+
+	p, err := rpc.Invoke(ctx, a, func(b *TxBuilder) {
+		if divisible {
+			b.Call(token, "transfer", from, recipient, amount.Value(), []byte(id), data)
+		} else {
+			b.Call(token, "transfer", recipient, []byte(id), data)
+		}
+		// transfer returns false rather than failing if it didn't happen
+		b.Script([]byte{byte(opcode.ASSERT)})
+	})
+	if err != nil {
+		return "", err
+	}
+	return p.Tx.Hash().StringLE(), nil
+}
+
+// NeoFSURI formats an object address as neofs:<container>/<object>
+func NeoFSURI(addr *address.Address) string {
+	return NEOFS_URI_SCHEME + addr.String()
+}
+
+// NeoFSGatewayURL is the HTTP gateway URL of an object, e.g. for an NFT's image property
+func NeoFSGatewayURL(gateway string, addr *address.Address) string {
+	return strings.TrimRight(gateway, "/") + "/" + addr.String()
+}
+
+// ParseNeoFSLocation reads an object address from a neofs: URI, a gateway URL or a bare <container>/<object>
+func ParseNeoFSLocation(s string) (*address.Address, error) {
+	s = strings.TrimPrefix(s, NEOFS_URI_SCHEME)
+	if i := strings.Index(s, "://"); i >= 0 {
+		s = s[i+3:]
+		// drop the gateway host
+		j := strings.IndexByte(s, '/')
+		if j < 0 {
+			return nil, fmt.Errorf("no object in %q", s)
+		}
+		s = s[j+1:]
+	}
+	addr := address.NewAddress()
+	if err := addr.Parse(s); err != nil {
+		return nil, fmt.Errorf("invalid NeoFS address %q: %w", s, err)
+	}
+	return addr, nil
+}
+
+// NFTMetadata describes an NFT whose media is a NeoFS object, in the properties NEP-11 wallets understand
+type NFTMetadata struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Image is the gateway URL of the media so wallets can show it
+	Image string `json:"image"`
+	// NeoFS is the neofs: URI of the media, which does not depend on a gateway
+	NeoFS string `json:"neofs"`
+}
+
+// NewNFTMetadata links an NFT to the NeoFS object holding its media, served through gateway, e.g. HTTP_GATEWAY_TESTNET.
+// Mint the token with the metadata's JSON, which is what most NFT contracts store as the token's properties
+func NewNFTMetadata(name, description string, media *address.Address, gateway string) *NFTMetadata {
+	return &NFTMetadata{
+		Name:        name,
+		Description: description,
+		Image:       NeoFSGatewayURL(gateway, media),
+		NeoFS:       NeoFSURI(media),
+	}
+}
+
+// Bytes is the metadata as JSON, ready to pass to a mint method
+func (m *NFTMetadata) Bytes() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// Properties is the metadata in the form returned by GetNep11Properties
+func (m *NFTMetadata) Properties() Nep11Properties {
+	p := Nep11Properties{"name": m.Name, "image": m.Image, NFT_PROPERTY_NEOFS: m.NeoFS}
+	if m.Description != "" {
+		p["description"] = m.Description
+	}
+	return p
+}
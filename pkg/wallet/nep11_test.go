@@ -0,0 +1,115 @@
+package wallet_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	neowallet "github.com/nspcc-dev/neo-go/pkg/wallet"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/object/address"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/stretchr/testify/assert"
+)
+
+// testNFTMedia is the NeoFS object holding the media of the token with id 0x01 of testNFT
+var testNFTMedia = mediaAddress("media").String()
+
+func mediaAddress(seed string) *address.Address {
+	c := cid.New()
+	c.SetSHA256(sha256.Sum256([]byte(seed + "container")))
+	o := oid.NewID()
+	o.SetSHA256(sha256.Sum256([]byte(seed + "object")))
+	addr := address.NewAddress()
+	addr.SetContainerID(c)
+	addr.SetObjectID(o)
+	return addr
+}
+
+func TestGetNep11Tokens(t *testing.T) {
+	ctx := context.Background()
+	node := newTestNode(t, netmode.TestNet)
+	node.nfts = result.NEP11Balances{Balances: []result.NEP11AssetBalance{{
+		Asset:  testNFT,
+		Tokens: []result.NEP11TokenBalance{{ID: "01", Amount: "1", LastUpdated: 5}, {ID: "ff00", Amount: "1", LastUpdated: 7}},
+	}}}
+	rpc, err := wallet.NewRPCClient(ctx, client.Options{}, wallet.RPC_NETWORK(node.URL))
+	assert.Nil(t, err, "error not nil")
+
+	tokens, err := wallet.GetNep11Tokens(ctx, rpc, "NX8GreRFGFK5wpGMWetpX93HmtrezGogzk")
+	assert.Nil(t, err, "error not nil")
+	assert.Len(t, tokens, 2)
+	assert.Equal(t, "NFT", tokens[0].Symbol)
+	assert.Equal(t, wallet.TokenID{0x01}, tokens[0].ID)
+	assert.Equal(t, wallet.TokenID{0xff, 0x00}, tokens[1].ID)
+	assert.Equal(t, "1", tokens[1].Amount.String())
+	assert.False(t, tokens[1].Divisible, "non-divisible token reported divisible")
+
+	data, err := json.Marshal(tokens[1])
+	assert.Nil(t, err, "error not nil")
+	var decoded wallet.Nep11Token
+	assert.Nil(t, json.Unmarshal(data, &decoded), "error not nil")
+	assert.Equal(t, tokens[1].ID, decoded.ID)
+}
+
+func TestGetNep11Properties(t *testing.T) {
+	ctx := context.Background()
+	node := newTestNode(t, netmode.TestNet)
+	rpc, err := wallet.NewRPCClient(ctx, client.Options{}, wallet.RPC_NETWORK(node.URL))
+	assert.Nil(t, err, "error not nil")
+
+	props, err := wallet.GetNep11Properties(ctx, rpc, testNFT, wallet.TokenID{0x01})
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, "Pump #1", props.String("name"))
+	assert.Equal(t, big.NewInt(3), props["rarity"])
+	addr, err := props.NeoFSAddress()
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, testNFTMedia, addr.String())
+}
+
+func TestTransferNep11Checks(t *testing.T) {
+	ctx := context.Background()
+	node := newTestNode(t, netmode.TestNet)
+	rpc, err := wallet.NewRPCClient(ctx, client.Options{}, wallet.RPC_NETWORK(node.URL))
+	assert.Nil(t, err, "error not nil")
+	acc, err := neowallet.NewAccount()
+	assert.Nil(t, err, "error not nil")
+
+	_, err = wallet.TransferNep11(ctx, rpc, acc, wallet.AmountFromInt64(2, 0), acc.Address, testNFT, wallet.TokenID{0x01}, nil)
+	assert.NotNil(t, err, "sent two of a non-divisible token")
+	_, err = wallet.TransferNep11(ctx, rpc, acc, wallet.AmountFromInt64(5, 1), acc.Address, testNFT, wallet.TokenID{0x01}, nil)
+	assert.NotNil(t, err, "sent half of a non-divisible token")
+	_, err = wallet.TransferNep11(ctx, rpc, acc, wallet.AmountFromInt64(1, 0), acc.Address, util.Uint160{6}, wallet.TokenID{0x01}, nil)
+	assert.NotNil(t, err, "sent a NEP-17 token as an NFT")
+}
+
+func TestNFTMetadata(t *testing.T) {
+	media := mediaAddress("picture")
+	m := wallet.NewNFTMetadata("Pump #2", "A gas pump", media, wallet.HTTP_GATEWAY_TESTNET+"/")
+	assert.Equal(t, wallet.HTTP_GATEWAY_TESTNET+"/"+media.String(), m.Image)
+	assert.Equal(t, "neofs:"+media.String(), m.NeoFS)
+
+	data, err := m.Bytes()
+	assert.Nil(t, err, "error not nil")
+	var props wallet.Nep11Properties
+	assert.Nil(t, json.Unmarshal(data, &props), "error not nil")
+	assert.Equal(t, m.Properties(), props)
+
+	// the media is found from the gateway URL when there is no neofs property
+	delete(props, wallet.NFT_PROPERTY_NEOFS)
+	addr, err := props.NeoFSAddress()
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, media.String(), addr.String())
+
+	_, err = wallet.Nep11Properties{"image": "https://example.com/cat.png"}.NeoFSAddress()
+	assert.NotNil(t, err, "found NeoFS object in a plain URL")
+	_, err = wallet.ParseNeoFSLocation("neofs:" + media.String())
+	assert.Nil(t, err, "error not nil")
+}
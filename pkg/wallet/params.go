@@ -0,0 +1,467 @@
+package wallet
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+var (
+	bigIntType    = reflect.TypeOf((*big.Int)(nil))
+	publicKeyType = reflect.TypeOf((*keys.PublicKey)(nil))
+	parameterType = reflect.TypeOf(smartcontract.Parameter{})
+)
+
+// ToParameter converts a Go value to a contract parameter of the matching type:
+//
+//	nil                              Any
+//	bool                             Boolean
+//	ints, uints, *big.Int            Integer
+//	string                           String
+//	[]byte                           ByteArray
+//	util.Uint160, util.Uint256       Hash160, Hash256
+//	*keys.PublicKey                  PublicKey
+//	slices and arrays                Array
+//	structs                          Array of the exported fields, as contracts see structs
+//	maps                             Map, sorted by key so the script is the same every time
+//
+// A smartcontract.Parameter is passed through as it is and pointers are followed
+func ToParameter(v interface{}) (smartcontract.Parameter, error) {
+	if v == nil {
+		return smartcontract.Parameter{Type: smartcontract.AnyType}, nil
+	}
+	return toParameter(reflect.ValueOf(v))
+}
+
+// ToParameters converts each of vs with ToParameter
+func ToParameters(vs ...interface{}) ([]smartcontract.Parameter, error) {
+	params := make([]smartcontract.Parameter, len(vs))
+	for i, v := range vs {
+		p, err := ToParameter(v)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %d: %w", i, err)
+		}
+		params[i] = p
+	}
+	return params, nil
+}
+
+func toParameter(v reflect.Value) (smartcontract.Parameter, error) {
+	switch v.Type() {
+	case parameterType:
+		return v.Interface().(smartcontract.Parameter), nil
+	case bigIntType:
+		if v.IsNil() {
+			return smartcontract.Parameter{Type: smartcontract.AnyType}, nil
+		}
+		n := v.Interface().(*big.Int)
+		if n.IsInt64() {
+			return smartcontract.Parameter{Type: smartcontract.IntegerType, Value: n.Int64()}, nil
+		}
+		return smartcontract.Parameter{Type: smartcontract.IntegerType, Value: new(big.Int).Set(n)}, nil
+	case publicKeyType:
+		if v.IsNil() {
+			return smartcontract.Parameter{Type: smartcontract.AnyType}, nil
+		}
+		return smartcontract.Parameter{Type: smartcontract.PublicKeyType, Value: v.Interface().(*keys.PublicKey).Bytes()}, nil
+	case reflect.TypeOf(util.Uint160{}):
+		return smartcontract.Parameter{Type: smartcontract.Hash160Type, Value: v.Interface().(util.Uint160)}, nil
+	case reflect.TypeOf(util.Uint256{}):
+		return smartcontract.Parameter{Type: smartcontract.Hash256Type, Value: v.Interface().(util.Uint256)}, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return smartcontract.Parameter{Type: smartcontract.AnyType}, nil
+		}
+		return toParameter(v.Elem())
+	case reflect.Bool:
+		return smartcontract.Parameter{Type: smartcontract.BoolType, Value: v.Bool()}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return smartcontract.Parameter{Type: smartcontract.IntegerType, Value: v.Int()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return toParameter(reflect.ValueOf(new(big.Int).SetUint64(v.Uint())))
+	case reflect.String:
+		return smartcontract.Parameter{Type: smartcontract.StringType, Value: v.String()}, nil
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return smartcontract.Parameter{Type: smartcontract.ByteArrayType, Value: b}, nil
+		}
+		items := make([]smartcontract.Parameter, v.Len())
+		for i := range items {
+			p, err := toParameter(v.Index(i))
+			if err != nil {
+				return smartcontract.Parameter{}, fmt.Errorf("item %d: %w", i, err)
+			}
+			items[i] = p
+		}
+		return smartcontract.Parameter{Type: smartcontract.ArrayType, Value: items}, nil
+	case reflect.Struct:
+		var items []smartcontract.Parameter
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			p, err := toParameter(v.Field(i))
+			if err != nil {
+				return smartcontract.Parameter{}, fmt.Errorf("field %s: %w", v.Type().Field(i).Name, err)
+			}
+			items = append(items, p)
+		}
+		return smartcontract.Parameter{Type: smartcontract.ArrayType, Value: items}, nil
+	case reflect.Map:
+		pairs := make([]smartcontract.ParameterPair, 0, v.Len())
+		order := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := toParameter(iter.Key())
+			if err != nil {
+				return smartcontract.Parameter{}, fmt.Errorf("key %v: %w", iter.Key(), err)
+			}
+			value, err := toParameter(iter.Value())
+			if err != nil {
+				return smartcontract.Parameter{}, fmt.Errorf("value of %v: %w", iter.Key(), err)
+			}
+			pairs = append(pairs, smartcontract.ParameterPair{Key: key, Value: value})
+			order = append(order, fmt.Sprint(iter.Key().Interface()))
+		}
+		sort.Sort(pairsByKey{pairs, order})
+		return smartcontract.Parameter{Type: smartcontract.MapType, Value: pairs}, nil
+	}
+	return smartcontract.Parameter{}, fmt.Errorf("unsupported type %s", v.Type())
+}
+
+type pairsByKey struct {
+	pairs []smartcontract.ParameterPair
+	keys  []string
+}
+
+func (p pairsByKey) Len() int           { return len(p.pairs) }
+func (p pairsByKey) Less(i, j int) bool { return p.keys[i] < p.keys[j] }
+func (p pairsByKey) Swap(i, j int) {
+	p.pairs[i], p.pairs[j] = p.pairs[j], p.pairs[i]
+	p.keys[i], p.keys[j] = p.keys[j], p.keys[i]
+}
+
+// EmitCall writes a script calling operation on contract with params. Unlike emit.AppCall it keeps the parameter
+// types, so strings stay strings and maps are supported
+func EmitCall(w *io.BinWriter, contract util.Uint160, operation string, params ...smartcontract.Parameter) error {
+	if err := emitArray(w, params); err != nil {
+		return err
+	}
+	emit.AppCallNoArgs(w, contract, operation, callflag.All)
+	return w.Err
+}
+
+func emitArray(w *io.BinWriter, items []smartcontract.Parameter) error {
+	if len(items) == 0 {
+		emit.Opcodes(w, opcode.NEWARRAY0)
+		return nil
+	}
+	for i := len(items) - 1; i >= 0; i-- {
+		if err := emitParameter(w, items[i]); err != nil {
+			return err
+		}
+	}
+	emit.Int(w, int64(len(items)))
+	emit.Opcodes(w, opcode.PACK)
+	return nil
+}
+
+func emitParameter(w *io.BinWriter, p smartcontract.Parameter) error {
+	if p.Value == nil {
+		switch p.Type {
+		case smartcontract.AnyType, smartcontract.InteropInterfaceType, smartcontract.ArrayType, smartcontract.MapType:
+		default:
+			return fmt.Errorf("%s parameter has no value", p.Type)
+		}
+	}
+	var ok bool
+	switch p.Type {
+	case smartcontract.AnyType, smartcontract.InteropInterfaceType:
+		if p.Value != nil {
+			return fmt.Errorf("%s parameter must be null", p.Type)
+		}
+		emit.Opcodes(w, opcode.PUSHNULL)
+		ok = true
+	case smartcontract.BoolType:
+		var b bool
+		if b, ok = p.Value.(bool); ok {
+			emit.Bool(w, b)
+		}
+	case smartcontract.IntegerType:
+		switch n := p.Value.(type) {
+		case int64:
+			emit.Int(w, n)
+			ok = true
+		case *big.Int:
+			if n.IsInt64() {
+				emit.Int(w, n.Int64())
+			} else {
+				emit.Bytes(w, bigint.ToBytes(n))
+				emit.Instruction(w, opcode.CONVERT, []byte{byte(stackitem.IntegerT)})
+			}
+			ok = true
+		}
+	case smartcontract.StringType:
+		var s string
+		if s, ok = p.Value.(string); ok {
+			emit.String(w, s)
+		}
+	case smartcontract.ByteArrayType, smartcontract.SignatureType:
+		var b []byte
+		if b, ok = p.Value.([]byte); ok {
+			emit.Bytes(w, b)
+		}
+	case smartcontract.PublicKeyType:
+		switch k := p.Value.(type) {
+		case []byte:
+			emit.Bytes(w, k)
+			ok = true
+		case *keys.PublicKey:
+			emit.Bytes(w, k.Bytes())
+			ok = true
+		}
+	case smartcontract.Hash160Type:
+		var h util.Uint160
+		if h, ok = p.Value.(util.Uint160); ok {
+			emit.Bytes(w, h.BytesBE())
+		}
+	case smartcontract.Hash256Type:
+		var h util.Uint256
+		if h, ok = p.Value.(util.Uint256); ok {
+			emit.Bytes(w, h.BytesBE())
+		}
+	case smartcontract.ArrayType:
+		var items []smartcontract.Parameter
+		if p.Value == nil {
+			ok = true
+		} else if items, ok = p.Value.([]smartcontract.Parameter); !ok {
+			break
+		}
+		if err := emitArray(w, items); err != nil {
+			return err
+		}
+	case smartcontract.MapType:
+		var pairs []smartcontract.ParameterPair
+		if p.Value == nil {
+			ok = true
+		} else if pairs, ok = p.Value.([]smartcontract.ParameterPair); !ok {
+			break
+		}
+		emit.Opcodes(w, opcode.NEWMAP)
+		for _, pair := range pairs {
+			emit.Opcodes(w, opcode.DUP)
+			if err := emitParameter(w, pair.Key); err != nil {
+				return err
+			}
+			if err := emitParameter(w, pair.Value); err != nil {
+				return err
+			}
+			emit.Opcodes(w, opcode.SETITEM)
+		}
+	default:
+		return fmt.Errorf("unsupported parameter type %s", p.Type)
+	}
+	if !ok {
+		return fmt.Errorf("invalid %s parameter value %T", p.Type, p.Value)
+	}
+	return w.Err
+}
+
+// FromStackItem decodes a stack item into the Go value dst points to, the reverse of ToParameter.
+// Integers decode into ints, uints and *big.Int, byte strings into string, []byte, util.Uint160, util.Uint256
+// and *keys.PublicKey, arrays and structs into slices and structs, maps into maps. dst may also be a stackitem.Item,
+// or an interface{} which gets the item's raw value. Null leaves pointers, slices and maps nil
+func FromStackItem(item stackitem.Item, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.New("destination must be a non-nil pointer")
+	}
+	return fromStackItem(item, v.Elem())
+}
+
+func fromStackItem(item stackitem.Item, v reflect.Value) error {
+	if item == nil {
+		item = stackitem.Null{}
+	}
+	if v.Kind() == reflect.Interface && v.NumMethod() == 0 {
+		if value := item.Value(); value != nil {
+			v.Set(reflect.ValueOf(value))
+		} else {
+			v.Set(reflect.Zero(v.Type()))
+		}
+		return nil
+	}
+	if reflect.TypeOf(item).AssignableTo(v.Type()) {
+		v.Set(reflect.ValueOf(item))
+		return nil
+	}
+	if _, isNull := item.(stackitem.Null); isNull {
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		return fmt.Errorf("can't decode null into %s", v.Type())
+	}
+
+	switch v.Type() {
+	case bigIntType:
+		n, err := item.TryInteger()
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(n))
+		return nil
+	case publicKeyType:
+		b, err := item.TryBytes()
+		if err != nil {
+			return err
+		}
+		key, err := keys.NewPublicKeyFromBytes(b, elliptic.P256())
+		if err != nil {
+			return fmt.Errorf("invalid public key: %w", err)
+		}
+		v.Set(reflect.ValueOf(key))
+		return nil
+	case reflect.TypeOf(util.Uint160{}):
+		b, err := item.TryBytes()
+		if err != nil {
+			return err
+		}
+		h, err := util.Uint160DecodeBytesBE(b)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(h))
+		return nil
+	case reflect.TypeOf(util.Uint256{}):
+		b, err := item.TryBytes()
+		if err != nil {
+			return err
+		}
+		h, err := util.Uint256DecodeBytesBE(b)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(h))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(v.Type().Elem())
+		if err := fromStackItem(item, elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.Bool:
+		b, err := item.TryBool()
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := item.TryInteger()
+		if err != nil {
+			return err
+		}
+		if !n.IsInt64() || v.OverflowInt(n.Int64()) {
+			return fmt.Errorf("%s overflows %s", n, v.Type())
+		}
+		v.SetInt(n.Int64())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := item.TryInteger()
+		if err != nil {
+			return err
+		}
+		if !n.IsUint64() || v.OverflowUint(n.Uint64()) {
+			return fmt.Errorf("%s overflows %s", n, v.Type())
+		}
+		v.SetUint(n.Uint64())
+	case reflect.String:
+		b, err := item.TryBytes()
+		if err != nil {
+			return err
+		}
+		v.SetString(string(b))
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if _, isArray := item.Value().([]stackitem.Item); !isArray {
+				b, err := item.TryBytes()
+				if err != nil {
+					return err
+				}
+				v.SetBytes(b)
+				return nil
+			}
+		}
+		items, ok := item.Value().([]stackitem.Item)
+		if !ok {
+			return fmt.Errorf("can't decode %s into %s", item.Type(), v.Type())
+		}
+		s := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i := range items {
+			if err := fromStackItem(items[i], s.Index(i)); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		v.Set(s)
+	case reflect.Struct:
+		items, ok := item.Value().([]stackitem.Item)
+		if !ok {
+			return fmt.Errorf("can't decode %s into %s", item.Type(), v.Type())
+		}
+		n := 0
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			if n >= len(items) {
+				return fmt.Errorf("%s has %d items, %s needs more", item.Type(), len(items), v.Type())
+			}
+			if err := fromStackItem(items[n], v.Field(i)); err != nil {
+				return fmt.Errorf("field %s: %w", v.Type().Field(i).Name, err)
+			}
+			n++
+		}
+	case reflect.Map:
+		elements, ok := item.Value().([]stackitem.MapElement)
+		if !ok {
+			return fmt.Errorf("can't decode %s into %s", item.Type(), v.Type())
+		}
+		m := reflect.MakeMapWithSize(v.Type(), len(elements))
+		for _, e := range elements {
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := fromStackItem(e.Key, key); err != nil {
+				return fmt.Errorf("map key: %w", err)
+			}
+			value := reflect.New(v.Type().Elem()).Elem()
+			if err := fromStackItem(e.Value, value); err != nil {
+				return fmt.Errorf("value of %v: %w", key, err)
+			}
+			m.SetMapIndex(key, value)
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("can't decode into %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package wallet_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/assert"
+)
+
+type testStruct struct {
+	Owner  util.Uint160
+	Amount *big.Int
+	Tags   []string
+	hidden int
+}
+
+// callArgs runs a call script up to the contract call and returns the arguments it would pass
+func callArgs(t *testing.T, params ...smartcontract.Parameter) []stackitem.Item {
+	w := io.NewBufBinWriter()
+	assert.Nil(t, wallet.EmitCall(w.BinWriter, util.Uint160{1}, "method", params...), "error not nil")
+	v := vm.New()
+	v.SyscallHandler = func(*vm.VM, uint32) error { return nil }
+	v.LoadScript(w.Bytes())
+	assert.Nil(t, v.Run(), "error not nil")
+	// the hash, method and call flags are on top of the arguments
+	args := v.Estack().Peek(3).Item()
+	return args.Value().([]stackitem.Item)
+}
+
+func TestParametersRoundTrip(t *testing.T) {
+	key, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	in := testStruct{Owner: util.Uint160{1, 2, 3}, Amount: huge, Tags: []string{"a", "b"}, hidden: 7}
+	props := map[string]int{"b": 2, "a": 1}
+
+	params, err := wallet.ToParameters(true, -5, uint64(1)<<63, "text", []byte{0, 1}, key.PublicKey(), in, props, nil)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, smartcontract.StringType, params[3].Type)
+	assert.Equal(t, smartcontract.ByteArrayType, params[4].Type)
+	assert.Equal(t, smartcontract.PublicKeyType, params[5].Type)
+	assert.Equal(t, smartcontract.ArrayType, params[6].Type)
+	assert.Equal(t, smartcontract.MapType, params[7].Type)
+	assert.Equal(t, "a", params[7].Value.([]smartcontract.ParameterPair)[0].Key.Value, "map not sorted")
+
+	args := callArgs(t, params...)
+	assert.Len(t, args, 9)
+
+	var (
+		b    bool
+		i    int
+		u    uint64
+		s    string
+		raw  []byte
+		pub  *keys.PublicKey
+		out  testStruct
+		m    map[string]int
+		null *big.Int
+	)
+	for n, dst := range []interface{}{&b, &i, &u, &s, &raw, &pub, &out, &m, &null} {
+		assert.Nil(t, wallet.FromStackItem(args[n], dst), "error not nil")
+	}
+	assert.True(t, b)
+	assert.Equal(t, -5, i)
+	assert.Equal(t, uint64(1)<<63, u)
+	assert.Equal(t, "text", s)
+	assert.Equal(t, []byte{0, 1}, raw)
+	assert.Equal(t, key.PublicKey().Bytes(), pub.Bytes())
+	assert.Equal(t, in.Owner, out.Owner)
+	assert.Equal(t, 0, huge.Cmp(out.Amount))
+	assert.Equal(t, in.Tags, out.Tags)
+	assert.Equal(t, 0, out.hidden)
+	assert.Equal(t, props, m)
+	assert.Nil(t, null)
+
+	var item stackitem.Item
+	assert.Nil(t, wallet.FromStackItem(args[7], &item), "error not nil")
+	assert.IsType(t, &stackitem.Map{}, item)
+	var small int8
+	assert.NotNil(t, wallet.FromStackItem(args[2], &small), "decoded an overflowing integer")
+	assert.NotNil(t, wallet.FromStackItem(args[0], s), "decoded into a non-pointer")
+
+	_, err = wallet.ToParameter(func() {})
+	assert.NotNil(t, err, "converted a func")
+}
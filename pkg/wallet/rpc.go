@@ -0,0 +1,276 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+)
+
+var (
+	ErrNoEndpoints     = errors.New("no RPC endpoints")
+	ErrNetworkMismatch = errors.New("RPC endpoint is on a different network")
+)
+
+// RPCClient is a long lived connection to a Neo N3 RPC node, shared by the wallet functions.
+// It fails over to the next endpoint when a node can't be reached, and caches the network magic and the native
+// contract hashes so they are fetched once. It is safe for concurrent use
+type RPCClient struct {
+	ctx       context.Context
+	opts      client.Options
+	endpoints []RPC_NETWORK
+
+	mu      sync.RWMutex
+	current int
+	cli     *client.Client
+	network netmode.Magic
+	natives map[string]util.Uint160
+	tokens  map[util.Uint160]*wallet.Token
+}
+
+// NewRPCClient connects to the first endpoint that answers, e.g. NewRPCClient(ctx, client.Options{}, RPC_TESTNET).
+// ctx is the lifetime of the client, cancel it to drop the connections. Set opts.RequestTimeout to bound each request
+func NewRPCClient(ctx context.Context, opts client.Options, endpoints ...RPC_NETWORK) (*RPCClient, error) {
+	if len(endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	r := &RPCClient{ctx: ctx, opts: opts, endpoints: endpoints}
+	if _, err := r.Client(ctx); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Client returns the neo-go client of the current endpoint, connecting if the last one failed
+func (r *RPCClient) Client(ctx context.Context) (*client.Client, error) {
+	r.mu.RLock()
+	cli := r.cli
+	r.mu.RUnlock()
+	if cli != nil {
+		return cli, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cli != nil {
+		return r.cli, nil
+	}
+	var lastErr error
+	for i := 0; i < len(r.endpoints); i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		idx := (r.current + i) % len(r.endpoints)
+		cli, err := r.connect(r.endpoints[idx])
+		if err != nil {
+			lastErr = fmt.Errorf("%s: %w", r.endpoints[idx], err)
+			continue
+		}
+		r.current, r.cli = idx, cli
+		return cli, nil
+	}
+	return nil, fmt.Errorf("can't connect to any RPC endpoint: %w", lastErr)
+}
+
+// connect is called with mu held
+func (r *RPCClient) connect(endpoint RPC_NETWORK) (*client.Client, error) {
+	cli, err := client.New(r.ctx, string(endpoint), r.opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := cli.Init(); err != nil {
+		return nil, err
+	}
+	if r.natives == nil {
+		natives, err := cli.GetNativeContracts()
+		if err != nil {
+			return nil, fmt.Errorf("can't get native contracts: %w", err)
+		}
+		r.network = cli.GetNetwork()
+		r.natives = make(map[string]util.Uint160, len(natives))
+		for _, n := range natives {
+			r.natives[n.Manifest.Name] = n.Hash
+		}
+	} else if cli.GetNetwork() != r.network {
+		return nil, fmt.Errorf("%w: %d, not %d", ErrNetworkMismatch, cli.GetNetwork(), r.network)
+	}
+	return cli, nil
+}
+
+// failover drops cli so that the next call connects to the following endpoint
+func (r *RPCClient) failover(cli *client.Client) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cli == cli {
+		r.cli = nil
+		r.current = (r.current + 1) % len(r.endpoints)
+	}
+}
+
+// Do calls f with the current client. If the node can't be reached f is retried on the next endpoint, once per
+// endpoint. Errors returned by a node that did answer are not retried. f must be safe to repeat: don't create and
+// send a new transaction in it, send a signed one instead, which is the same transaction on every node
+func (r *RPCClient) Do(ctx context.Context, f func(cli *client.Client) error) error {
+	var lastErr error
+	for i := 0; i < len(r.endpoints); i++ {
+		cli, err := r.Client(ctx)
+		if err != nil {
+			return err
+		}
+		err = call(ctx, cli, f)
+		if err == nil || !unreachable(ctx, err) {
+			return err
+		}
+		lastErr = err
+		r.failover(cli)
+	}
+	return lastErr
+}
+
+// call returns when f does or ctx is done. neo-go requests can't be cancelled, one left behind ends with its timeout
+func call(ctx context.Context, cli *client.Client, f func(cli *client.Client) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- f(cli) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// unreachable reports whether err means the node could not be asked, rather than that it answered with an error.
+// neo-go surfaces transport failures as *url.Error and 5xx responses without a JSON body as "HTTP 5xx/..."
+func unreachable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) || strings.Contains(err.Error(), "HTTP 5")
+}
+
+// Invoke builds a transaction with script, acc as the sender, signs and sends it. Building moves to the next endpoint
+// like Do; the signed transaction is the same on every node, so sending does too
+func (r *RPCClient) Invoke(ctx context.Context, acc *wallet.Account, script func(b *TxBuilder)) (*PreparedTx, error) {
+	return r.InvokeAs(ctx, TxSigner{Account: acc, Scopes: transaction.CalledByEntry}, script)
+}
+
+// InvokeAs is Invoke with the sender's witness scope given by s, for calls that reach beyond the called contract
+func (r *RPCClient) InvokeAs(ctx context.Context, s TxSigner, script func(b *TxBuilder)) (*PreparedTx, error) {
+	var p *PreparedTx
+	err := r.Do(ctx, func(cli *client.Client) (err error) {
+		b := NewTxBuilder(cli).AddSigner(s)
+		script(b)
+		p, err = b.Build()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := p.Sign(s.Account); err != nil {
+		return nil, fmt.Errorf("can't sign transaction: %w", err)
+	}
+	return p, r.SendTx(ctx, p.Tx)
+}
+
+// SendTx sends a signed transaction, moving to the next endpoint like Do
+func (r *RPCClient) SendTx(ctx context.Context, tx *transaction.Transaction) error {
+	err := r.Do(ctx, func(cli *client.Client) error {
+		_, err := cli.SendRawTransaction(tx)
+		return err
+	})
+	// a send that reached the first node may be retried on the next, which already has it
+	var rpcErr *response.Error
+	if errors.As(err, &rpcErr) && rpcErr.Code == response.ErrAlreadyExists.Code {
+		err = nil
+	}
+	if err != nil {
+		return fmt.Errorf("can't send transaction: %w", err)
+	}
+	return nil
+}
+
+// Network is the magic of the network the endpoints are on
+func (r *RPCClient) Network() netmode.Magic {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.network
+}
+
+// NativeHash returns the hash of a native contract by name, e.g. nativenames.Gas
+func (r *RPCClient) NativeHash(name string) (util.Uint160, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	h, ok := r.natives[name]
+	if !ok {
+		return util.Uint160{}, fmt.Errorf("no native contract %s", name)
+	}
+	return h, nil
+}
+
+// TokenInfo returns the symbol, decimals and standard of a NEP-17 or NEP-11 token, fetching them once per token
+func (r *RPCClient) TokenInfo(ctx context.Context, token util.Uint160) (*wallet.Token, error) {
+	r.mu.RLock()
+	info, ok := r.tokens[token]
+	r.mu.RUnlock()
+	if ok {
+		return info, nil
+	}
+	err := r.Do(ctx, func(cli *client.Client) error {
+		cs, err := cli.GetContractStateByHash(token)
+		if err != nil {
+			return err
+		}
+		var standard string
+		for _, s := range cs.Manifest.SupportedStandards {
+			if s == manifest.NEP17StandardName || s == manifest.NEP11StandardName {
+				standard = s
+				break
+			}
+		}
+		if standard == "" {
+			return fmt.Errorf("%s is neither a NEP-17 nor a NEP-11 token", cs.Manifest.Name)
+		}
+		// symbol and decimals are the same methods in both standards
+		symbol, err := cli.NEP17Symbol(token)
+		if err != nil {
+			return err
+		}
+		decimals, err := cli.NEP17Decimals(token)
+		if err != nil {
+			return err
+		}
+		info = wallet.NewToken(token, cs.Manifest.Name, symbol, decimals, standard)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't get token info of %s: %w", token.StringLE(), err)
+	}
+	r.mu.Lock()
+	if r.tokens == nil {
+		r.tokens = make(map[util.Uint160]*wallet.Token)
+	}
+	r.tokens[token] = info
+	r.mu.Unlock()
+	return info, nil
+}
+
+// Endpoint is the endpoint currently in use
+func (r *RPCClient) Endpoint() RPC_NETWORK {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.endpoints[r.current]
+}
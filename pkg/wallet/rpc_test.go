@@ -0,0 +1,157 @@
+package wallet_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/configwizard/gaspump-api/internal/rpctest"
+	"github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/assert"
+)
+
+// testNode answers the calls made by RPCClient like a node on network would
+type testNode struct {
+	*rpctest.Node
+	height uint32
+
+	mu        sync.Mutex
+	logs      map[util.Uint256]*result.ApplicationLog
+	transfers result.NEP17Transfers
+	nfts      result.NEP11Balances
+	params    []interface{}
+}
+
+// testNFT is a non-divisible NEP-11 contract deployed on every testNode
+var testNFT = util.Uint160{0x11}
+
+// addBlock persists a block holding the transactions of logs
+func (n *testNode) addBlock(logs ...*result.ApplicationLog) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, l := range logs {
+		n.logs[l.Container] = l
+	}
+	atomic.AddUint32(&n.height, 1)
+}
+
+func newTestNode(t *testing.T, network netmode.Magic) *testNode {
+	n := &testNode{Node: rpctest.NewNode(t, network), height: 1, logs: map[util.Uint256]*result.ApplicationLog{}}
+	n.AddContract(rpctest.NewContract(t, "NFT", 1, testNFT, manifest.NEP11StandardName))
+	n.Handle("invokefunction", func(params []interface{}) (interface{}, error) {
+		// every token but testNFT is GAS
+		inv := result.Invoke{State: "HALT"}
+		isNFT := params[0].(string) == testNFT.StringLE()
+		switch params[1].(string) {
+		case "symbol":
+			inv.Stack = []stackitem.Item{stackitem.NewByteArray([]byte("GAS"))}
+			if isNFT {
+				inv.Stack = []stackitem.Item{stackitem.NewByteArray([]byte("NFT"))}
+			}
+		case "decimals":
+			inv.Stack = []stackitem.Item{stackitem.NewBigInteger(big.NewInt(8))}
+			if isNFT {
+				inv.Stack = []stackitem.Item{stackitem.NewBigInteger(big.NewInt(0))}
+			}
+		case "properties":
+			inv.Stack = []stackitem.Item{stackitem.NewMapWithValue([]stackitem.MapElement{
+				{Key: stackitem.Make("name"), Value: stackitem.Make("Pump #1")},
+				{Key: stackitem.Make("rarity"), Value: stackitem.Make(3)},
+				{Key: stackitem.Make("neofs"), Value: stackitem.Make(testNFTMedia)},
+			})}
+		}
+		return inv, nil
+	})
+	n.Handle("getnep17transfers", func(params []interface{}) (interface{}, error) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		n.params = params
+		return n.transfers, nil
+	})
+	n.Handle("getnep11balances", func([]interface{}) (interface{}, error) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		return n.nfts, nil
+	})
+	n.Handle("getblockcount", func([]interface{}) (interface{}, error) {
+		return atomic.LoadUint32(&n.height), nil
+	})
+	n.Handle("getapplicationlog", func(params []interface{}) (interface{}, error) {
+		h, err := util.Uint256DecodeStringLE(params[0].(string))
+		assert.Nil(t, err, "error not nil")
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		log, ok := n.logs[h]
+		if !ok {
+			return nil, rpctest.ErrUnknown("transaction")
+		}
+		return log, nil
+	})
+	n.Handle("getpeers", func([]interface{}) (interface{}, error) {
+		peers := result.NewGetPeers()
+		peers.AddConnected([]string{"127.0.0.1:20333"})
+		return peers, nil
+	})
+	return n
+}
+
+func TestRPCClientFailover(t *testing.T) {
+	ctx := context.Background()
+	dead := newTestNode(t, netmode.TestNet)
+	dead.Close()
+	first := newTestNode(t, netmode.TestNet)
+	otherNetwork := newTestNode(t, netmode.MainNet)
+	second := newTestNode(t, netmode.TestNet)
+
+	_, err := wallet.NewRPCClient(ctx, client.Options{})
+	assert.True(t, errors.Is(err, wallet.ErrNoEndpoints), "created without endpoints")
+
+	rpc, err := wallet.NewRPCClient(ctx, client.Options{}, wallet.RPC_NETWORK(dead.URL), wallet.RPC_NETWORK(first.URL),
+		wallet.RPC_NETWORK(otherNetwork.URL), wallet.RPC_NETWORK(second.URL))
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, wallet.RPC_NETWORK(first.URL), rpc.Endpoint())
+	assert.Equal(t, netmode.TestNet, rpc.Network())
+	gas, err := rpc.NativeHash(nativenames.Gas)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, util.Uint160{6}, gas)
+	_, err = rpc.NativeHash("NoSuchContract")
+	assert.NotNil(t, err, "unknown native found")
+
+	// the handshake is done once, later calls reuse the connection
+	handshake := first.Calls()
+	for i := 0; i < 3; i++ {
+		peers, err := wallet.GetPeers(ctx, rpc)
+		assert.Nil(t, err, "error not nil")
+		assert.Len(t, peers, 1)
+	}
+	assert.Equal(t, handshake+3, first.Calls())
+
+	// a node going away moves calls past the endpoint on another network to the next one
+	first.Close()
+	_, err = wallet.GetPeers(ctx, rpc)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, wallet.RPC_NETWORK(second.URL), rpc.Endpoint())
+
+	// errors from a node that answered are returned, not retried
+	err = rpc.Do(ctx, func(cli *client.Client) error {
+		_, err := cli.GetBestBlockHash()
+		return err
+	})
+	assert.NotNil(t, err, "error is nil")
+	assert.Equal(t, wallet.RPC_NETWORK(second.URL), rpc.Endpoint())
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = wallet.GetPeers(cancelled, rpc)
+	assert.True(t, errors.Is(err, context.Canceled), "cancelled context ignored")
+}
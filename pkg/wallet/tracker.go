@@ -0,0 +1,250 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+// ErrTrackerStopped is reported for transactions still pending when the tracker stops
+var ErrTrackerStopped = errors.New("tracker stopped")
+
+// Nep17Transfer is a decoded NEP-17 Transfer notification. From is nil for a mint, To is nil for a burn
+type Nep17Transfer struct {
+	Token  util.Uint160  `json:"token"`
+	From   *util.Uint160 `json:"from"`
+	To     *util.Uint160 `json:"to"`
+	Amount *big.Int      `json:"amount"`
+}
+
+// TxResult is the outcome of a tracked transaction. Err is set if it expired or was not seen before the tracker stopped,
+// otherwise Log holds its application log
+type TxResult struct {
+	TxHash         util.Uint256           `json:"txHash"`
+	Log            *result.ApplicationLog `json:"log"`
+	VMState        vm.State               `json:"vmState"`
+	GasConsumed    int64                  `json:"gasConsumed"`
+	FaultException string                 `json:"faultException"`
+	Transfers      []Nep17Transfer        `json:"transfers"`
+	Err            error                  `json:"-"`
+}
+
+// Succeeded reports whether the transaction was included and halted
+func (r *TxResult) Succeeded() bool {
+	return r.Err == nil && r.VMState == vm.HaltState
+}
+
+// NewTxResult decodes the first execution of an application log
+func NewTxResult(log *result.ApplicationLog) *TxResult {
+	r := &TxResult{TxHash: log.Container, Log: log}
+	if len(log.Executions) == 0 {
+		r.Err = fmt.Errorf("transaction %s has no executions", log.Container.StringLE())
+		return r
+	}
+	exec := log.Executions[0]
+	r.VMState, r.GasConsumed, r.FaultException = exec.VMState, exec.GasConsumed, exec.FaultException
+	r.Transfers = DecodeNep17Transfers(exec.Events)
+	return r
+}
+
+// DecodeNep17Transfers picks the NEP-17 Transfer notifications out of events. NEP-11 transfers, which carry a token id,
+// and malformed notifications are skipped
+func DecodeNep17Transfers(events []state.NotificationEvent) []Nep17Transfer {
+	var transfers []Nep17Transfer
+	for _, e := range events {
+		if e.Name != "Transfer" || e.Item == nil {
+			continue
+		}
+		items, ok := e.Item.Value().([]stackitem.Item)
+		if !ok || len(items) != 3 {
+			continue
+		}
+		from, err := transferAddress(items[0])
+		if err != nil {
+			continue
+		}
+		to, err := transferAddress(items[1])
+		if err != nil {
+			continue
+		}
+		amount, err := items[2].TryInteger()
+		if err != nil {
+			continue
+		}
+		transfers = append(transfers, Nep17Transfer{Token: e.ScriptHash, From: from, To: to, Amount: amount})
+	}
+	return transfers
+}
+
+func transferAddress(item stackitem.Item) (*util.Uint160, error) {
+	if _, ok := item.(stackitem.Null); ok {
+		return nil, nil
+	}
+	b, err := item.TryBytes()
+	if err != nil {
+		return nil, err
+	}
+	u, err := util.Uint160DecodeBytesBE(b)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+type trackedTx struct {
+	validUntilBlock uint32
+	listeners       []func(*TxResult)
+}
+
+// Tracker watches submitted transactions until they are included or expire and reports each outcome once.
+// Start it with go tracker.Run(ctx) and add transactions with Watch or WatchChan
+type Tracker struct {
+	rpc      *RPCClient
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending map[util.Uint256]*trackedTx
+	wake    chan struct{}
+}
+
+func NewTracker(rpc *RPCClient) *Tracker {
+	return &Tracker{
+		rpc:      rpc,
+		interval: TX_POLL_INTERVAL,
+		pending:  make(map[util.Uint256]*trackedTx),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// SetPollInterval changes how often pending transactions are checked. Call it before Run
+func (t *Tracker) SetPollInterval(d time.Duration) {
+	t.interval = d
+}
+
+// Watch calls onDone once txHash is included or validUntilBlock has passed. validUntilBlock of 0 waits until the
+// tracker stops. onDone is called from the tracker's goroutine and should not block
+func (t *Tracker) Watch(txHash util.Uint256, validUntilBlock uint32, onDone func(*TxResult)) {
+	t.mu.Lock()
+	tx, ok := t.pending[txHash]
+	if !ok {
+		tx = &trackedTx{validUntilBlock: validUntilBlock}
+		t.pending[txHash] = tx
+	}
+	tx.listeners = append(tx.listeners, onDone)
+	t.mu.Unlock()
+
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+// WatchChan is Watch reporting on a channel, which receives one result
+func (t *Tracker) WatchChan(txHash util.Uint256, validUntilBlock uint32) <-chan *TxResult {
+	ch := make(chan *TxResult, 1)
+	t.Watch(txHash, validUntilBlock, func(r *TxResult) {
+		ch <- r
+	})
+	return ch
+}
+
+// WatchPrepared watches a transaction built with TxBuilder, using its ValidUntilBlock
+func (t *Tracker) WatchPrepared(p *PreparedTx) <-chan *TxResult {
+	return t.WatchChan(p.Tx.Hash(), p.Tx.ValidUntilBlock)
+}
+
+// Pending is how many transactions are still being watched
+func (t *Tracker) Pending() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.pending)
+}
+
+// Run checks pending transactions until ctx is done, then reports ErrTrackerStopped for the ones left
+func (t *Tracker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+	for {
+		t.poll(ctx)
+		select {
+		case <-ctx.Done():
+			t.stop(ctx.Err())
+			return ctx.Err()
+		case <-ticker.C:
+		case <-t.wake:
+		}
+	}
+}
+
+func (t *Tracker) poll(ctx context.Context) {
+	t.mu.Lock()
+	hashes := make([]util.Uint256, 0, len(t.pending))
+	for h := range t.pending {
+		hashes = append(hashes, h)
+	}
+	t.mu.Unlock()
+	if len(hashes) == 0 {
+		return
+	}
+
+	var height uint32
+	err := t.rpc.Do(ctx, func(cli *client.Client) (err error) {
+		height, err = cli.GetBlockCount()
+		return err
+	})
+	if err != nil {
+		// try again next time, the node may be back by then
+		return
+	}
+	for _, h := range hashes {
+		var log *result.ApplicationLog
+		err := t.rpc.Do(ctx, func(cli *client.Client) (err error) {
+			log, err = cli.GetApplicationLog(h, nil)
+			return err
+		})
+		if err == nil {
+			t.done(h, NewTxResult(log))
+			continue
+		}
+		t.mu.Lock()
+		validUntilBlock := t.pending[h].validUntilBlock
+		t.mu.Unlock()
+		// the block count was read before the log, so the transaction could not have been in a later block
+		if validUntilBlock != 0 && height > validUntilBlock+1 {
+			t.done(h, &TxResult{TxHash: h, Err: fmt.Errorf("%w: %s", ErrTxExpired, h.StringLE())})
+		}
+	}
+}
+
+func (t *Tracker) done(h util.Uint256, r *TxResult) {
+	t.mu.Lock()
+	tx := t.pending[h]
+	delete(t.pending, h)
+	t.mu.Unlock()
+	for _, f := range tx.listeners {
+		f(r)
+	}
+}
+
+func (t *Tracker) stop(cause error) {
+	t.mu.Lock()
+	pending := t.pending
+	t.pending = make(map[util.Uint256]*trackedTx)
+	t.mu.Unlock()
+	for h, tx := range pending {
+		r := &TxResult{TxHash: h, Err: fmt.Errorf("%w: %s", ErrTrackerStopped, cause)}
+		for _, f := range tx.listeners {
+			f(r)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package wallet_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/assert"
+)
+
+func transferEvent(token, from, to util.Uint160, amount int64) state.NotificationEvent {
+	return state.NotificationEvent{ScriptHash: token, Name: "Transfer", Item: stackitem.NewArray([]stackitem.Item{
+		stackitem.NewByteArray(from.BytesBE()),
+		stackitem.NewByteArray(to.BytesBE()),
+		stackitem.NewBigInteger(big.NewInt(amount)),
+	})}
+}
+
+func TestDecodeNep17Transfers(t *testing.T) {
+	token, from, to := util.Uint160{1}, util.Uint160{2}, util.Uint160{3}
+	mint := state.NotificationEvent{ScriptHash: token, Name: "Transfer", Item: stackitem.NewArray([]stackitem.Item{
+		stackitem.Null{}, stackitem.NewByteArray(to.BytesBE()), stackitem.NewBigInteger(big.NewInt(5)),
+	})}
+	nft := state.NotificationEvent{ScriptHash: token, Name: "Transfer", Item: stackitem.NewArray([]stackitem.Item{
+		stackitem.NewByteArray(from.BytesBE()), stackitem.NewByteArray(to.BytesBE()), stackitem.NewBigInteger(big.NewInt(1)),
+		stackitem.NewByteArray([]byte("token id")),
+	})}
+	other := state.NotificationEvent{ScriptHash: token, Name: "Approval", Item: stackitem.NewArray(nil)}
+
+	transfers := wallet.DecodeNep17Transfers([]state.NotificationEvent{transferEvent(token, from, to, 100), mint, nft, other})
+	assert.Len(t, transfers, 2)
+	assert.Equal(t, token, transfers[0].Token)
+	assert.Equal(t, from, *transfers[0].From)
+	assert.Equal(t, to, *transfers[0].To)
+	assert.Equal(t, int64(100), transfers[0].Amount.Int64())
+	assert.Nil(t, transfers[1].From, "mint has a sender")
+	assert.Equal(t, int64(5), transfers[1].Amount.Int64())
+}
+
+func TestTracker(t *testing.T) {
+	node := newTestNode(t, netmode.TestNet)
+	rpc, err := wallet.NewRPCClient(context.Background(), client.Options{}, wallet.RPC_NETWORK(node.URL))
+	assert.Nil(t, err, "error not nil")
+	tracker := wallet.NewTracker(rpc)
+	tracker.SetPollInterval(10 * time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error)
+	go func() { stopped <- tracker.Run(ctx) }()
+
+	included, faulted, expired, forgotten := util.Uint256{1}, util.Uint256{2}, util.Uint256{3}, util.Uint256{4}
+	token, from, to := util.Uint160{1}, util.Uint160{2}, util.Uint160{3}
+	includedCh := tracker.WatchChan(included, 10)
+	var fromCallback *wallet.TxResult
+	callbackDone := make(chan struct{})
+	tracker.Watch(faulted, 10, func(r *wallet.TxResult) {
+		fromCallback = r
+		close(callbackDone)
+	})
+	expiredCh := tracker.WatchChan(expired, 2)
+	forgottenCh := tracker.WatchChan(forgotten, 0)
+
+	node.addBlock(&result.ApplicationLog{Container: included, Executions: []state.Execution{{
+		Trigger:     trigger.Application,
+		VMState:     vm.HaltState,
+		GasConsumed: 1000,
+		Stack:       []stackitem.Item{},
+		Events:      []state.NotificationEvent{transferEvent(token, from, to, 7)},
+	}}}, &result.ApplicationLog{Container: faulted, Executions: []state.Execution{{
+		Trigger:        trigger.Application,
+		VMState:        vm.FaultState,
+		Stack:          []stackitem.Item{},
+		FaultException: "at instruction 0 (ABORT)",
+	}}})
+
+	r := <-includedCh
+	assert.Nil(t, r.Err, "error not nil")
+	assert.True(t, r.Succeeded())
+	assert.Equal(t, int64(1000), r.GasConsumed)
+	assert.Len(t, r.Transfers, 1)
+	assert.Equal(t, int64(7), r.Transfers[0].Amount.Int64())
+
+	<-callbackDone
+	assert.False(t, fromCallback.Succeeded())
+	assert.Equal(t, vm.FaultState, fromCallback.VMState)
+	assert.Equal(t, "at instruction 0 (ABORT)", fromCallback.FaultException)
+
+	// blocks 2 and 3 go by without it
+	node.addBlock()
+	node.addBlock()
+	r = <-expiredCh
+	assert.True(t, errors.Is(r.Err, wallet.ErrTxExpired), "not expired")
+	assert.Equal(t, 1, tracker.Pending())
+
+	cancel()
+	assert.True(t, errors.Is(<-stopped, context.Canceled), "not cancelled")
+	r = <-forgottenCh
+	assert.True(t, errors.Is(r.Err, wallet.ErrTrackerStopped), "not stopped")
+	assert.Equal(t, 0, tracker.Pending())
+}
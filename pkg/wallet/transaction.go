@@ -0,0 +1,425 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/configwizard/gaspump-api/pkg/signer"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	sccontext "github.com/nspcc-dev/neo-go/pkg/smartcontract/context"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+)
+
+// TX_POLL_INTERVAL is how often the node is asked whether a transaction has been included
+const TX_POLL_INTERVAL = 2 * time.Second
+
+var (
+	ErrTxExpired = errors.New("transaction expired before it was included in a block")
+	ErrNotSigned = errors.New("transaction is missing signatures")
+)
+
+// TxSigner is an account that has to witness the transaction. The account may be locked or watch-only
+// when the transaction is signed offline, only its verification script is needed to build it
+type TxSigner struct {
+	Account          *wallet.Account
+	Scopes           transaction.WitnessScope
+	AllowedContracts []util.Uint160
+	AllowedGroups    keys.PublicKeys
+}
+
+func (s TxSigner) signer() transaction.Signer {
+	return transaction.Signer{
+		Account:          s.Account.Contract.ScriptHash(),
+		Scopes:           s.Scopes,
+		AllowedContracts: s.AllowedContracts,
+		AllowedGroups:    s.AllowedGroups,
+	}
+}
+
+// TxBuilder builds a contract invocation: add calls and signers, dry-run it with TestInvoke, then Build it to see
+// the fees before signing and submitting. The first signer is the sender and pays the fees
+type TxBuilder struct {
+	cli      *client.Client
+	script   *io.BufBinWriter
+	signers  []TxSigner
+	extraNet int64
+	extraSys int64
+	validFor uint32
+	err      error
+}
+
+func NewTxBuilder(cli *client.Client) *TxBuilder {
+	return &TxBuilder{cli: cli, script: io.NewBufBinWriter()}
+}
+
+// Call appends a contract call to the script. args are converted with ToParameter
+func (b *TxBuilder) Call(contract util.Uint160, operation string, args ...interface{}) *TxBuilder {
+	if b.err != nil {
+		return b
+	}
+	params, err := ToParameters(args...)
+	if err != nil {
+		b.err = fmt.Errorf("can't emit call to %s: %w", operation, err)
+		return b
+	}
+	return b.CallParams(contract, operation, params...)
+}
+
+// CallParams appends a contract call with parameters of the given types
+func (b *TxBuilder) CallParams(contract util.Uint160, operation string, params ...smartcontract.Parameter) *TxBuilder {
+	if b.err == nil {
+		if err := EmitCall(b.script.BinWriter, contract, operation, params...); err != nil {
+			b.err = fmt.Errorf("can't emit call to %s: %w", operation, err)
+		}
+	}
+	return b
+}
+
+// Script appends raw script bytes
+func (b *TxBuilder) Script(script []byte) *TxBuilder {
+	if b.err == nil {
+		b.script.WriteBytes(script)
+	}
+	return b
+}
+
+// AddSigner adds an account that must witness the transaction
+func (b *TxBuilder) AddSigner(s TxSigner) *TxBuilder {
+	if s.Account == nil || s.Account.Contract == nil {
+		b.err = errors.New("signer has no account contract")
+		return b
+	}
+	for _, existing := range b.signers {
+		if existing.Account.Contract.ScriptHash().Equals(s.Account.Contract.ScriptHash()) {
+			b.err = fmt.Errorf("%s is already a signer", s.Account.Address)
+			return b
+		}
+	}
+	b.signers = append(b.signers, s)
+	return b
+}
+
+// AddAccount adds a signer whose witness is only valid in the called contract, the usual choice
+func (b *TxBuilder) AddAccount(acc *wallet.Account) *TxBuilder {
+	return b.AddSigner(TxSigner{Account: acc, Scopes: transaction.CalledByEntry})
+}
+
+// SetExtraFees adds to the estimated system and network fees, e.g. to get ahead in the mempool
+func (b *TxBuilder) SetExtraFees(systemFee, networkFee int64) *TxBuilder {
+	b.extraSys, b.extraNet = systemFee, networkFee
+	return b
+}
+
+// SetValidFor makes the transaction valid for this many blocks rather than the node's default
+func (b *TxBuilder) SetValidFor(blocks uint32) *TxBuilder {
+	b.validFor = blocks
+	return b
+}
+
+func (b *TxBuilder) txSigners() []transaction.Signer {
+	signers := make([]transaction.Signer, len(b.signers))
+	for i := range b.signers {
+		signers[i] = b.signers[i].signer()
+	}
+	return signers
+}
+
+// TestInvoke runs the script on the node without sending it. Nothing is changed on chain
+func (b *TxBuilder) TestInvoke() (*InvokeResult, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.script.Len() == 0 {
+		return nil, errors.New("transaction has no script")
+	}
+	res, err := b.cli.InvokeScript(b.script.Bytes(), b.txSigners())
+	if err != nil {
+		return nil, fmt.Errorf("can't test invoke: %w", err)
+	}
+	return &InvokeResult{Invoke: res}, nil
+}
+
+// Build test invokes the script, fails if it would fault, and returns the transaction with its fees set
+// but not yet signed
+func (b *TxBuilder) Build() (*PreparedTx, error) {
+	if len(b.signers) == 0 {
+		return nil, errors.New("transaction has no signers")
+	}
+	inv, err := b.TestInvoke()
+	if err != nil {
+		return nil, err
+	}
+	if err := inv.Err(); err != nil {
+		return nil, err
+	}
+
+	tx := transaction.New(b.script.Bytes(), inv.GasConsumed+b.extraSys)
+	tx.Signers = b.txSigners()
+	if b.validFor > 0 {
+		height, err := b.cli.GetBlockCount()
+		if err != nil {
+			return nil, fmt.Errorf("can't get block count: %w", err)
+		}
+		tx.ValidUntilBlock = height + b.validFor
+	} else if tx.ValidUntilBlock, err = b.cli.CalculateValidUntilBlock(); err != nil {
+		return nil, err
+	}
+	accounts := make([]*wallet.Account, len(b.signers))
+	for i := range b.signers {
+		accounts[i] = b.signers[i].Account
+	}
+	if err := b.cli.AddNetworkFee(tx, b.extraNet, accounts...); err != nil {
+		return nil, fmt.Errorf("can't calculate network fee: %w", err)
+	}
+	p, err := NewPreparedTx(tx, b.cli.GetNetwork(), accounts...)
+	if err != nil {
+		return nil, err
+	}
+	p.Invocation = inv
+	return p, nil
+}
+
+// PreparedTx is a built transaction waiting for signatures
+type PreparedTx struct {
+	Tx         *transaction.Transaction
+	Invocation *InvokeResult
+	network    netmode.Magic
+	accounts   []*wallet.Account
+}
+
+// NewPreparedTx wraps a transaction built elsewhere, e.g. one received for offline signing.
+// accounts are the accounts of its signers, in the same order
+func NewPreparedTx(tx *transaction.Transaction, network netmode.Magic, accounts ...*wallet.Account) (*PreparedTx, error) {
+	if len(accounts) != len(tx.Signers) {
+		return nil, fmt.Errorf("transaction has %d signers, %d accounts given", len(tx.Signers), len(accounts))
+	}
+	if len(tx.Scripts) == 0 {
+		tx.Scripts = make([]transaction.Witness, len(accounts))
+	} else if len(tx.Scripts) != len(accounts) {
+		return nil, fmt.Errorf("transaction has %d signers but %d witnesses", len(tx.Signers), len(tx.Scripts))
+	}
+	for i := range accounts {
+		if accounts[i].Contract == nil || !accounts[i].Contract.ScriptHash().Equals(tx.Signers[i].Account) {
+			return nil, fmt.Errorf("account %d does not match signer %s", i, tx.Signers[i].Account.StringLE())
+		}
+		if tx.Scripts[i].VerificationScript == nil {
+			tx.Scripts[i].VerificationScript = accounts[i].GetVerificationScript()
+		}
+	}
+	return &PreparedTx{Tx: tx, network: network, accounts: accounts}, nil
+}
+
+// SystemFee, NetworkFee and TotalFee are in fixed8 GAS and are paid by the first signer
+func (p *PreparedTx) SystemFee() int64  { return p.Tx.SystemFee }
+func (p *PreparedTx) NetworkFee() int64 { return p.Tx.NetworkFee }
+func (p *PreparedTx) TotalFee() int64   { return p.Tx.SystemFee + p.Tx.NetworkFee }
+
+func (p *PreparedTx) signerIndex(h util.Uint160) (int, error) {
+	for i := range p.Tx.Signers {
+		if p.Tx.Signers[i].Account.Equals(h) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("%s is not a signer of the transaction", h.StringLE())
+}
+
+// Sign adds the witness of an unlocked single signature account
+func (p *PreparedTx) Sign(acc *wallet.Account) error {
+	s, err := signer.NewAccountSigner(acc)
+	if err != nil {
+		return err
+	}
+	return p.SignWith(context.Background(), s)
+}
+
+// SignWith adds a witness made by s, which may be remote, for the standard account of its key
+func (p *PreparedTx) SignWith(ctx context.Context, s signer.Signer) error {
+	i, err := p.signerIndex(s.PublicKey().GetScriptHash())
+	if err != nil {
+		return err
+	}
+	sig, err := s.Sign(signer.WithPayloadType(ctx, signer.PayloadTransaction), signer.SchemeRFC6979, SignedData(p.network, p.Tx))
+	if err != nil {
+		return err
+	}
+	p.Tx.Scripts[i].InvocationScript = append([]byte{byte(opcode.PUSHDATA1), byte(len(sig))}, sig...)
+	return nil
+}
+
+// Context exports the transaction in neo-go's signing context format, so it can be signed offline,
+// e.g. with `neo-go wallet sign` or SignMultiSigContext, and brought back with Complete
+func (p *PreparedTx) Context() *sccontext.ParameterContext {
+	return sccontext.NewParameterContext(MULTISIG_CONTEXT_TYPE, p.network, p.Tx)
+}
+
+// Complete copies the witnesses collected in a signing context into the transaction
+func (p *PreparedTx) Complete(pc *sccontext.ParameterContext) error {
+	for i, s := range p.Tx.Signers {
+		if len(p.Tx.Scripts[i].InvocationScript) != 0 || len(p.accounts[i].Contract.Parameters) == 0 {
+			continue
+		}
+		w, err := pc.GetWitness(s.Account)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrNotSigned, err)
+		}
+		p.Tx.Scripts[i] = *w
+	}
+	return nil
+}
+
+// Signed reports whether every signer has a witness
+func (p *PreparedTx) Signed() bool {
+	for i := range p.Tx.Scripts {
+		if len(p.Tx.Scripts[i].InvocationScript) == 0 && len(p.accounts[i].Contract.Parameters) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Send submits the signed transaction without waiting
+func (p *PreparedTx) Send(cli *client.Client) (util.Uint256, error) {
+	if !p.Signed() {
+		return util.Uint256{}, ErrNotSigned
+	}
+	h, err := cli.SendRawTransaction(p.Tx)
+	if err != nil {
+		return util.Uint256{}, fmt.Errorf("can't send transaction: %w", err)
+	}
+	return h, nil
+}
+
+// Submit sends the transaction and waits for its application log. The log is returned even if the transaction faulted
+func (p *PreparedTx) Submit(ctx context.Context, cli *client.Client) (*result.ApplicationLog, error) {
+	h, err := p.Send(cli)
+	if err != nil {
+		return nil, err
+	}
+	return WaitForApplicationLog(ctx, cli, h, p.Tx.ValidUntilBlock)
+}
+
+// WaitForApplicationLog polls until the transaction is in a block. If validUntilBlock is not 0 it returns ErrTxExpired
+// once the chain has passed it without including the transaction
+func WaitForApplicationLog(ctx context.Context, cli *client.Client, txHash util.Uint256, validUntilBlock uint32) (*result.ApplicationLog, error) {
+	ticker := time.NewTicker(TX_POLL_INTERVAL)
+	defer ticker.Stop()
+	for {
+		if log, err := cli.GetApplicationLog(txHash, nil); err == nil {
+			return log, nil
+		}
+		if validUntilBlock != 0 {
+			if height, err := cli.GetBlockCount(); err == nil && height > validUntilBlock+1 {
+				// the block at validUntilBlock may have been persisted between the two calls
+				if log, err := cli.GetApplicationLog(txHash, nil); err == nil {
+					return log, nil
+				}
+				return nil, fmt.Errorf("%w: %s", ErrTxExpired, txHash.StringLE())
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("transaction %s not accepted: %w", txHash.StringLE(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExecutionError returns an error if the first execution in the log did not halt
+func ExecutionError(log *result.ApplicationLog) error {
+	if len(log.Executions) == 0 {
+		return fmt.Errorf("transaction %s has no executions", log.Container.StringLE())
+	}
+	if exec := log.Executions[0]; exec.VMState != vm.HaltState {
+		return fmt.Errorf("transaction %s failed: %s", log.Container.StringLE(), exec.FaultException)
+	}
+	return nil
+}
+
+// InvokeResult is the outcome of a test invocation with helpers to decode the result stack
+type InvokeResult struct {
+	*result.Invoke
+}
+
+// Err returns the fault exception if the script would fail
+func (r *InvokeResult) Err() error {
+	if r.State != vm.HaltState.String() {
+		return fmt.Errorf("script would fail with %s: %s", r.State, r.FaultException)
+	}
+	return nil
+}
+
+// Notifications the script would emit
+func (r *InvokeResult) Events() []state.NotificationEvent {
+	return r.Notifications
+}
+
+// Item returns the i'th item of the result stack
+func (r *InvokeResult) Item(i int) (stackitem.Item, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	if i < 0 || i >= len(r.Stack) {
+		return nil, fmt.Errorf("result stack has %d items, no item %d", len(r.Stack), i)
+	}
+	return r.Stack[i], nil
+}
+
+func (r *InvokeResult) Int(i int) (*big.Int, error) {
+	item, err := r.Item(i)
+	if err != nil {
+		return nil, err
+	}
+	return item.TryInteger()
+}
+
+func (r *InvokeResult) Bool(i int) (bool, error) {
+	item, err := r.Item(i)
+	if err != nil {
+		return false, err
+	}
+	return item.TryBool()
+}
+
+func (r *InvokeResult) Bytes(i int) ([]byte, error) {
+	item, err := r.Item(i)
+	if err != nil {
+		return nil, err
+	}
+	return item.TryBytes()
+}
+
+func (r *InvokeResult) String(i int) (string, error) {
+	b, err := r.Bytes(i)
+	return string(b), err
+}
+
+func (r *InvokeResult) Hash160(i int) (util.Uint160, error) {
+	b, err := r.Bytes(i)
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	return util.Uint160DecodeBytesBE(b)
+}
+
+// Decode decodes the i'th item of the result stack into dst with FromStackItem
+func (r *InvokeResult) Decode(i int, dst interface{}) error {
+	item, err := r.Item(i)
+	if err != nil {
+		return err
+	}
+	return FromStackItem(item, dst)
+}
@@ -0,0 +1,120 @@
+package wallet_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/configwizard/gaspump-api/pkg/signer"
+	"github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	neowallet "github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTx(t *testing.T, accounts ...*neowallet.Account) *wallet.PreparedTx {
+	tx := transaction.New([]byte{byte(opcode.PUSH1)}, 100)
+	tx.ValidUntilBlock = 1000
+	for _, acc := range accounts {
+		tx.Signers = append(tx.Signers, transaction.Signer{Account: acc.Contract.ScriptHash(), Scopes: transaction.CalledByEntry})
+	}
+	p, err := wallet.NewPreparedTx(tx, netmode.TestNet, accounts...)
+	assert.Nil(t, err, "error not nil")
+	return p
+}
+
+func TestPreparedTxSign(t *testing.T) {
+	var accounts []*neowallet.Account
+	var pubs []*keys.PublicKey
+	for i := 0; i < 2; i++ {
+		key, err := keys.NewPrivateKey()
+		assert.Nil(t, err, "error not nil")
+		accounts = append(accounts, neowallet.NewAccountFromPrivateKey(key))
+		pubs = append(pubs, key.PublicKey())
+	}
+	p := newTestTx(t, accounts...)
+	assert.False(t, p.Signed())
+	_, err := p.Send(nil)
+	assert.True(t, errors.Is(err, wallet.ErrNotSigned), "sent without signatures")
+
+	// signing out of order still puts each witness in its signer's slot
+	assert.Nil(t, p.Sign(accounts[1]), "error not nil")
+	assert.Nil(t, p.SignWith(context.Background(), signer.NewKeySigner(&accounts[0].PrivateKey().PrivateKey)), "error not nil")
+	assert.True(t, p.Signed())
+	digest := hash.NetSha256(uint32(netmode.TestNet), p.Tx)
+	for i := range accounts {
+		w := p.Tx.Scripts[i]
+		assert.Equal(t, accounts[i].GetVerificationScript(), w.VerificationScript)
+		assert.Len(t, w.InvocationScript, 66)
+		assert.Equal(t, byte(opcode.PUSHDATA1), w.InvocationScript[0])
+		assert.True(t, pubs[i].Verify(w.InvocationScript[2:], digest.BytesBE()), "signature invalid")
+	}
+	assert.Equal(t, int64(100), p.TotalFee())
+
+	other, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	assert.NotNil(t, p.Sign(neowallet.NewAccountFromPrivateKey(other)), "signed by a stranger")
+}
+
+func TestPreparedTxContext(t *testing.T) {
+	key, err := keys.NewPrivateKey()
+	assert.Nil(t, err, "error not nil")
+	acc := neowallet.NewAccountFromPrivateKey(key)
+	watchOnly := &neowallet.Account{Address: acc.Address, Contract: acc.Contract}
+
+	// build with an account that holds no key, sign elsewhere, bring the witness back
+	p := newTestTx(t, watchOnly)
+	pc := p.Context()
+	assert.NotNil(t, p.Complete(pc), "completed without signatures")
+	sig := key.SignHashable(uint32(netmode.TestNet), pc.Verifiable)
+	assert.Nil(t, pc.AddSignature(acc.Contract.ScriptHash(), acc.Contract, key.PublicKey(), sig), "error not nil")
+	assert.Nil(t, p.Complete(pc), "error not nil")
+	assert.True(t, p.Signed())
+	digest := hash.NetSha256(uint32(netmode.TestNet), p.Tx)
+	assert.True(t, key.PublicKey().Verify(p.Tx.Scripts[0].InvocationScript[2:], digest.BytesBE()), "signature invalid")
+
+	_, err = wallet.NewPreparedTx(p.Tx, netmode.TestNet)
+	assert.NotNil(t, err, "accounts do not match signers")
+}
+
+func TestInvokeResult(t *testing.T) {
+	h := util.Uint160{1, 2, 3}
+	r := &wallet.InvokeResult{Invoke: &result.Invoke{
+		State: vm.HaltState.String(),
+		Stack: []stackitem.Item{
+			stackitem.NewBigInteger(big.NewInt(42)),
+			stackitem.NewBool(true),
+			stackitem.NewByteArray([]byte("NEO")),
+			stackitem.NewByteArray(h.BytesBE()),
+		},
+	}}
+	assert.Nil(t, r.Err(), "error not nil")
+	i, err := r.Int(0)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, int64(42), i.Int64())
+	b, err := r.Bool(1)
+	assert.Nil(t, err, "error not nil")
+	assert.True(t, b)
+	s, err := r.String(2)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, "NEO", s)
+	u, err := r.Hash160(3)
+	assert.Nil(t, err, "error not nil")
+	assert.Equal(t, h, u)
+	_, err = r.Int(4)
+	assert.NotNil(t, err, "no such item")
+
+	r.State, r.FaultException = vm.FaultState.String(), "at instruction 3 (THROW)"
+	assert.NotNil(t, r.Err(), "fault not reported")
+	_, err = r.Int(0)
+	assert.NotNil(t, err, "decoded a faulted result")
+}
@@ -11,7 +11,6 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neofs-sdk-go/owner"
-	"math/big"
 )
 
 const (
@@ -81,10 +80,11 @@ func ByteArrayToString(byteArray []byte) string {
 		return hex.EncodeToString(byteArray)	
 }
 
+// PrivateKeyFromHexString parses a hex encoded private key, see ParsePrivateKeyHex
 func PrivateKeyFromHexString(hexString string) (*ecdsa.PrivateKey, error) {
-	pk := new(ecdsa.PrivateKey)
-    pk.D, _ = new(big.Int).SetString(hexString, 16)
-    pk.PublicKey.Curve = elliptic.P256()
-    pk.PublicKey.X, pk.PublicKey.Y = pk.PublicKey.Curve.ScalarBaseMult(pk.D.Bytes())
-    return pk, nil
+	key, err := ParsePrivateKeyHex(hexString)
+	if err != nil {
+		return nil, err
+	}
+	return &key.PrivateKey, nil
 }
@@ -5,19 +5,16 @@ import (
 	"crypto/ecdsa"
 	"errors"
 	"fmt"
+	"math/big"
 	"github.com/nspcc-dev/neo-go/cli/flags"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
-	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
 	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
-	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
-	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
-	"strconv"
 	"strings"
 )
 
@@ -89,105 +86,90 @@ func UnlockWallet(path, address, password string) (*wallet.Account, error) {
 }
 type Nep17Tokens struct {
 	Asset util.Uint160 `json:"asset"`
-	Amount uint64 `json:"amount""`
+	Amount Amount `json:"amount"`
 	Symbol string `json:"symbol"`
 	Info wallet.Token `json:"meta"`
 	Error error `json:"error"`
 }
-func GetNep17Balances(walletAddress string, network RPC_NETWORK) (map[string]Nep17Tokens, error) {
-	ctx := context.Background()
-	// use endpoint addresses of public RPC nodes, e.g. from https://dora.coz.io/monitor
-	cli, err := client.New(ctx, string(network), client.Options{})
-	if err != nil {
-		return map[string]Nep17Tokens{}, err
-	}
-	err = cli.Init()
-
+// GetNep17Balances returns the wallet's balances keyed by token symbol. A token whose details or amount can't be read
+// is kept with its Error set, keyed by its hash in LE if the symbol is unknown
+func GetNep17Balances(ctx context.Context, rpc *RPCClient, walletAddress string) (map[string]Nep17Tokens, error) {
+	recipient, err := StringToUint160(walletAddress)
 	if err != nil {
 		return map[string]Nep17Tokens{}, err
 	}
-	recipient, err := StringToUint160(walletAddress)
+	var balances *result.NEP17Balances
+	err = rpc.Do(ctx, func(cli *client.Client) (err error) {
+		balances, err = cli.GetNEP17Balances(recipient)
+		return err
+	})
 	if err != nil {
 		return map[string]Nep17Tokens{}, err
 	}
-	balances, err := cli.GetNEP17Balances(recipient)
 	tokens := make(map[string]Nep17Tokens)
 	for _, v := range balances.Balances {
-		tokInfo := Nep17Tokens{}
-		symbol, err := cli.NEP17Symbol(v.Asset)
+		tokInfo := Nep17Tokens{Asset: v.Asset}
+		info, err := rpc.TokenInfo(ctx, v.Asset)
 		if err != nil {
 			tokInfo.Error = err
+			tokens[v.Asset.StringLE()] = tokInfo
 			continue
 		}
-		tokInfo.Symbol = symbol
-		fmt.Println(v.Asset, v.Asset)
-		number, err := strconv.ParseUint(v.Amount, 10, 64)
-		if err != nil {
-			tokInfo.Error = err
-			continue
-		}
-		tokInfo.Amount = number
-
-		info, err := cli.NEP17TokenInfo(v.Asset)
-		if err != nil {
-			tokInfo.Error = err
+		tokInfo.Symbol = info.Symbol
+		tokInfo.Info = *info
+		number, ok := new(big.Int).SetString(v.Amount, 10)
+		if !ok {
+			tokInfo.Error = fmt.Errorf("invalid amount %q", v.Amount)
+			tokens[info.Symbol] = tokInfo
 			continue
 		}
-		tokInfo.Info = *info
-		tokens[symbol] = tokInfo
+		tokInfo.Amount = NewAmount(number, info.Decimals)
+		tokens[info.Symbol] = tokInfo
 	}
 
 	return tokens, nil
 }
 //TransferToken transfer Nep17 token to another wallets, for instance use address here https://testcdn.fs.neo.org/doc/integrations/endpoints/
 //simple example https://gist.github.com/alexvanin/4f22937b99990243a60b7abf68d7458c
-func TransferToken(a *wallet.Account, amount int64, walletTo string, token util.Uint160, network RPC_NETWORK) (string, error) {
-	ctx := context.Background()
-	// use endpoint addresses of public RPC nodes, e.g. from https://dora.coz.io/monitor
-	cli, err := client.New(ctx, string(network), client.Options{})
+//amount is converted to the token's decimals, e.g. ParseAmount("1.5", 8) sends 1.5 GAS
+func TransferToken(ctx context.Context, rpc *RPCClient, a *wallet.Account, amount Amount, walletTo string, token util.Uint160) (string, error) {
+	recipient, err := StringToUint160(walletTo)
 	if err != nil {
 		return "", err
 	}
-	err = cli.Init()
+	info, err := rpc.TokenInfo(ctx, token)
 	if err != nil {
 		return "", err
 	}
-	recipient, err := StringToUint160(walletTo)
+	amount, err = amount.Rescale(info.Decimals)
 	if err != nil {
 		return "", err
 	}
-	txHash, err := cli.TransferNEP17(a, recipient, token, amount, 0, nil, nil)
-	le := txHash.StringLE()
-	return le, err
-}
-//todo ...
-func GenerateMultiSignWalletFromSigners() {
-//	https://github.com/nspcc-dev/neo-go/blob/fdf80dbdc56d5f634908a5f0eb5ada2d9c7565af/docs/notary.md
-//useful read https://github.com/nspcc-dev/neo-go/blob/d5e11e0a75403fc56f48f23c13d25597a5d5f5a5/pkg/wallet/account_test.go#L91
-//https://medium.com/neoresearch/understanding-multisig-on-neo-df9c9c1403b1
-	//https://github.com/nspcc-dev/neo-go/blob/d5e11e0a75403fc56f48f23c13d25597a5d5f5a5/pkg/wallet/account.go#L196-L197
-
-	//example public keys
-	//hexs := []string{
-	//	//insert your key here
-	//	"02b3622bf4017bdfe317c58aed5f4c753f206b7db896046fa7d774bbc4bf7f8dc2",
-	//	"02103a7f7dd016558597f7960d27c516a4394fd968b9e65155eb4b013e4040406e",
-	//	"02a7bc55fe8684e0119768d104ba30795bdcc86619e864add26156723ed185cd62",
-	//	"03d90c07df63e690ce77912e10ab51acc944b66860237b608c4f8f8309e71ee699",
-	//}
-	//make sure YOUR public key is the first one so you can pay for the transaction
+	if amount.Sign() <= 0 {
+		return "", errors.New("amount must be positive")
+	}
+	from, err := StringToUint160(a.Address)
+	if err != nil {
+		return "", err
+	}
+	p, err := rpc.Invoke(ctx, a, func(b *TxBuilder) {
+		b.Call(token, "transfer", from, recipient, amount.Value(), nil)
+	})
+	if err != nil {
+		return "", err
+	}
+	return p.Tx.Hash().StringLE(), nil
 }
-func GetPeers(ntwk RPC_NETWORK) ([]result.Peer, error){
-	ctx := context.Background()
-	// use endpoint addresses of public RPC nodes, e.g. from https://dora.coz.io/monitor
-	cli, err := client.New(ctx, string(ntwk), client.Options{})
+func GetPeers(ctx context.Context, rpc *RPCClient) ([]result.Peer, error) {
+	var peers *result.GetPeers
+	err := rpc.Do(ctx, func(cli *client.Client) (err error) {
+		peers, err = cli.GetPeers()
+		return err
+	})
 	if err != nil {
 		return []result.Peer{}, err
 	}
-
-	err = cli.Init()
-	peers, err := cli.GetPeers()
-	return peers.Connected, err
+	return peers.Connected, nil
 }
 
 func ConvertScriptHashToAddressString(scriptHash string) (util.Uint160, string, error){
@@ -200,98 +182,29 @@ func ConvertScriptHashToAddressString(scriptHash string) (util.Uint160, string,
 	return contractAddress, Uint160ToString(contractAddress), nil
 
 }
-// CreateTransactionFromFunctionCall creates a transaction to call a function on a smart contract) that still requires executing
-// Before this is ready for sending
-// Consider using https://pkg.go.dev/github.com/nspcc-dev/neo-go/pkg/rpc/client#Client.CreateTxFromScript as an alternative
-func CreateTransactionFromFunctionCall(contractScriptHash string, operation string, network RPC_NETWORK, acc *wallet.Account, params []smartcontract.Parameter) (util.Uint256, *transaction.Transaction, error){
-	ctx := context.Background()
-	// use endpoint addresses of public RPC nodes, e.g. from https://dora.coz.io/monitor
-	cli, err := client.New(ctx, string(network), client.Options{})
-	if err != nil {
-		return util.Uint256{}, &transaction.Transaction{}, fmt.Errorf("can't create client %w\n", err)
-	}
-	err = cli.Init()
-
-	script := io.NewBufBinWriter()
-
+// CreateTransactionFromFunctionCall calls a function on a smart contract with acc as the sender, signs and sends it.
+// The transaction is test invoked first and is not sent if it would fail. Use NewTxBuilder to see the fees before sending
+func CreateTransactionFromFunctionCall(ctx context.Context, rpc *RPCClient, contractScriptHash string, operation string, acc *wallet.Account, params []smartcontract.Parameter) (util.Uint256, *transaction.Transaction, error) {
 	contractAddress, _, err := ConvertScriptHashToAddressString(contractScriptHash)
 	if err != nil {
-		return util.Uint256{}, &transaction.Transaction{}, err
-	}
-	account, err := StringToUint160(acc.Address)
-	if err != nil {
-		return util.Uint256{}, &transaction.Transaction{}, err
-	}
-
-	var args []interface{}
-	for _, v := range params {
-		args = append(args, v.Value)
-	}
-
-	//callflag.All could be restricted? Should it be passed in?
-	//operation e.g "symbol" - smart contract function to call.
-	emit.AppCall(script.BinWriter, contractAddress, operation, callflag.All, args...) //call the function (dry run)
-	tx := transaction.New(script.Bytes(), 0)
-
-	var signers []transaction.Signer
-	signer := transaction.Signer{
-		Account:          account, //the wallet that is allowed to execute the transaction
-		Scopes:           transaction.CalledByEntry,
-		AllowedContracts: nil, //not meaningful in the case of CalledByEntry
-		AllowedGroups:    nil,
-	}
-	signers = append(signers, signer)
-	tx.Signers = signers //do i need to do this?
-	witness := transaction.Witness{ //when/where do we set witnesses?
-		InvocationScript:   script.Bytes(),
-		VerificationScript: acc.GetVerificationScript(),
-	}
-	tx.Scripts = []transaction.Witness{witness}
-
-	testInvoke, err := cli.InvokeFunction(contractAddress, operation, params, signers)
-	if err != nil {
-		return util.Uint256{}, nil, fmt.Errorf("error invoking [test] function %w\r\n", err)
-	}
-	validUntilBlock, _ := cli.CalculateValidUntilBlock()
-	tx.ValidUntilBlock = validUntilBlock
-	fmt.Printf("tstTX %d - %s - %v\r\n", testInvoke.GasConsumed, testInvoke.FaultException, testInvoke.Transaction)
-	systemFee := testInvoke.GasConsumed          //gas consumed invoking contract
-	networkFee, err := cli.CalculateNetworkFee(tx) //calculating network networkFee
-	if err != nil {
-		return util.Uint256{}, nil, fmt.Errorf("error calculatng network fee %w\r\n", err)
-	}
-	tx.SystemFee = systemFee
-	fmt.Printf("gas consumed (system fee) %d, (network fee) %d, invoking function\r\n", systemFee, networkFee)
-	//adding network networkFee and gasConsumed to transaction with the wallet account paying
-	err = cli.AddNetworkFee(tx, networkFee, acc)
-	if err != nil {
-		return util.Uint256{}, nil, fmt.Errorf("error adding network fee %w\r\n", err)
+		return util.Uint256{}, nil, err
 	}
-	err = acc.SignTx(cli.GetNetwork(), tx)
+	p, err := rpc.Invoke(ctx, acc, func(b *TxBuilder) {
+		b.CallParams(contractAddress, operation, params...)
+	})
 	if err != nil {
-		return util.Uint256{}, nil, fmt.Errorf("error signing transaction %w\r\n", err)
+		return util.Uint256{}, nil, err
 	}
-	//and when you are ready you can invoke it
-	rawTransaction, err := cli.SendRawTransaction(tx)
-
-	if err != nil {
-		return util.Uint256{}, nil, fmt.Errorf("error sending raw transaction %w\r\n", err)
-	}
-
-	fmt.Printf("sent transaction %+v - ID %s\r\n", rawTransaction, rawTransaction.StringLE())
-	return rawTransaction, tx, nil //return the signed transaction
+	return p.Tx.Hash(), p.Tx, nil
 }
 
-func GetLogForTransaction(network RPC_NETWORK, transactionID util.Uint256) (*result.ApplicationLog, error) {
-	ctx := context.Background()
-	// use endpoint addresses of public RPC nodes, e.g. from https://dora.coz.io/monitor
-	cli, err := client.New(ctx, string(network), client.Options{})
-	if err != nil {
-		return &result.ApplicationLog{}, fmt.Errorf("can't create client %w\n", err)
-	}
-	err = cli.Init()
+func GetLogForTransaction(ctx context.Context, rpc *RPCClient, transactionID util.Uint256) (*result.ApplicationLog, error) {
 	trig := trigger.All
-	log, err := cli.GetApplicationLog(transactionID, &trig)
+	var log *result.ApplicationLog
+	err := rpc.Do(ctx, func(cli *client.Client) (err error) {
+		log, err = cli.GetApplicationLog(transactionID, &trig)
+		return err
+	})
 	return log, err
 }
 
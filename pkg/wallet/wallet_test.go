@@ -1,9 +1,14 @@
 package wallet_test
 
 import (
+	"context"
     "encoding/hex" 
 	"fmt"
 	"github.com/configwizard/gaspump-api/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -37,3 +42,26 @@ func TestGenerateWallet(t *testing.T) {
 	bytePublicKey := hex.EncodeToString(w.Accounts[0].PrivateKey().PublicKey().Bytes())
     fmt.Println("test key hex:", bytePublicKey)
 }
+
+func TestGetNep17Balances(t *testing.T) {
+	ctx := context.Background()
+	node := newTestNode(t, netmode.TestNet)
+	unknown := util.Uint160{0x99}
+	node.Handle("getnep17balances", func([]interface{}) (interface{}, error) {
+		return result.NEP17Balances{Balances: []result.NEP17Balance{
+			{Asset: util.Uint160{6}, Amount: "150000000"},
+			{Asset: unknown, Amount: "1"},
+		}}, nil
+	})
+	rpc, err := wallet.NewRPCClient(ctx, client.Options{}, wallet.RPC_NETWORK(node.URL))
+	assert.Nil(t, err, "error not nil")
+
+	balances, err := wallet.GetNep17Balances(ctx, rpc, "NX8GreRFGFK5wpGMWetpX93HmtrezGogzk")
+	assert.Nil(t, err, "error not nil")
+	assert.Len(t, balances, 2)
+	assert.Nil(t, balances["GAS"].Error, "error not nil")
+	assert.Equal(t, "1.5", balances["GAS"].Amount.String())
+	// a token whose contract can't be read is reported, not dropped
+	assert.NotNil(t, balances[unknown.StringLE()].Error, "unreadable token has no error")
+	assert.Equal(t, unknown, balances[unknown.StringLE()].Asset)
+}